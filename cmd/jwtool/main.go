@@ -0,0 +1,481 @@
+package main
+
+// ----------------------------------------------------------------------------------
+// jwtool (https://github.com/waldurbas/got): decode, verify and mint JWT tokens
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) verify ohne Parse, mint -exp ab jetzt
+// 2026.10.19 (wu) decode ohne Parse
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+//
+// jwtool decode <token>
+// jwtool verify -secret geheim <token>
+// jwtool verify -key pub.pem -aud web -leeway 30s <token>
+// jwtool verify -key jwks.json <token>
+// jwtool mint -alg ES256 -key priv.pem -kid k1 -claims claims.json -exp 15m
+//
+// token "-" oder ohne: von stdin, "Bearer " wird entfernt
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/waldurbas/got/jwt"
+)
+
+// stdout #Ausgabe, in Tests ersetzt
+var stdout io.Writer = os.Stdout
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "decode":
+		err = cmdDecode(os.Args[2:])
+	case "verify":
+		err = cmdVerify(os.Args[2:])
+	case "mint":
+		err = cmdMint(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "jwtool:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+  jwtool decode [-dec key] [token]
+  jwtool verify (-secret s | -key file) [-dec key] [-iss s] [-aud s] [-sub s] [-leeway d] [-legacy] [token]
+  jwtool mint (-secret s | -key file) [-alg a] [-kid k] [-claims file] [-exp d] [-enc key]
+
+key file: PEM, JWK or JWKS; -dec/-enc: oct JWK or JWKS for encrypted tokens (JWE)`)
+	os.Exit(2)
+}
+
+// readToken #Argument, "-" oder stdin
+func readToken(fs *flag.FlagSet) (string, error) {
+	s := fs.Arg(0)
+	if s == "" || s == "-" {
+		b, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && b == "" {
+			return "", errors.New("no token")
+		}
+		s = b
+	}
+
+	s = strings.TrimSpace(s)
+	for _, p := range []string{"Bearer ", "bearer ", "JWT "} {
+		s = strings.TrimPrefix(s, p)
+	}
+
+	return s, nil
+}
+
+// decodePart #base64url, auch mit Padding oder Standard-Alphabet (legacy)
+func decodePart(s string) (string, error) {
+	s = strings.TrimRight(s, "=")
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		b, err = base64.RawStdEncoding.DecodeString(s)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// decodeRaw #Header und Payload ohne jwt.Parse: keine Pruefung von alg,
+// Claims unveraendert (kein zusaetzliches iat), JWE mit -dec wird zuerst entschluesselt;
+// RawData bleibt fuer die Signaturpruefung erhalten
+func decodeRaw(s string, dec string) (*jwt.JWToken, error) {
+	if jwt.IsEncrypted(s) {
+		if dec == "" {
+			hdr, _ := decodePart(strings.Split(s, ".")[0])
+			return nil, fmt.Errorf("token is encrypted (JWE header %s), use -dec", hdr)
+		}
+
+		ks, err := loadKeySet(dec)
+		if err != nil {
+			return nil, err
+		}
+
+		b, _, err := ks.Decrypt(s)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt: %v", err)
+		}
+		s = string(b)
+	}
+
+	ss := strings.Split(s, ".")
+	if len(ss) != 3 {
+		return nil, fmt.Errorf("token has %d parts, expected 3", len(ss))
+	}
+
+	t := &jwt.JWToken{RawData: s, Head: jwt.TMap{}, Claims: jwt.TMap{}}
+	for i, m := range []jwt.TMap{t.Head, t.Claims} {
+		js, err := decodePart(ss[i])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", partName[i], err)
+		}
+
+		if err := json.Unmarshal([]byte(js), &m); err != nil {
+			return nil, fmt.Errorf("%s: %v", partName[i], err)
+		}
+	}
+
+	return t, nil
+}
+
+var partName = []string{"header", "payload"}
+
+func cmdDecode(args []string) error {
+	fs := flag.NewFlagSet("decode", flag.ExitOnError)
+	dec := fs.String("dec", "", "key for encrypted tokens")
+	fs.Parse(args)
+
+	s, err := readToken(fs)
+	if err != nil {
+		return err
+	}
+
+	t, err := decodeRaw(s, *dec)
+	if err != nil {
+		return err
+	}
+
+	printToken(t)
+	return nil
+}
+
+func printToken(t *jwt.JWToken) {
+	h, _ := json.MarshalIndent(t.Head, "", "  ")
+	c, _ := json.MarshalIndent(t.Claims, "", "  ")
+
+	fmt.Fprintf(stdout, "Header:\n%s\n\nClaims:\n%s\n", h, c)
+
+	now := time.Now()
+	first := true
+	for _, k := range []string{"iat", "nbf", "exp"} {
+		if _, ok := t.Claims[k]; !ok {
+			continue
+		}
+
+		if first {
+			fmt.Fprintln(stdout, "\nTimes:")
+			first = false
+		}
+
+		tm := t.Claims.AsTime(k)
+		fmt.Fprintf(stdout, "  %s: %s (%s)\n", k, tm.Format("2006-01-02 15:04:05 MST"), relTime(tm, now))
+	}
+}
+
+// relTime #"vor 5m0s" / "in 10m0s"
+func relTime(t time.Time, now time.Time) string {
+	d := t.Sub(now).Round(time.Second)
+	if d < 0 {
+		return (-d).String() + " ago"
+	}
+
+	return "in " + d.String()
+}
+
+func cmdVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	secret := fs.String("secret", "", "HMAC secret")
+	keyFile := fs.String("key", "", "PEM, JWK or JWKS file")
+	dec := fs.String("dec", "", "key for encrypted tokens")
+	iss := fs.String("iss", "", "expected issuer")
+	aud := fs.String("aud", "", "expected audience")
+	sub := fs.String("sub", "", "expected subject")
+	leeway := fs.Duration("leeway", 0, "allowed clock skew")
+	legacy := fs.Bool("legacy", false, "accept legacy hex signatures")
+	fs.Parse(args)
+
+	s, err := readToken(fs)
+	if err != nil {
+		return err
+	}
+
+	t, err := decodeRaw(s, *dec)
+	if err != nil {
+		return err
+	}
+
+	jwt.LegacyHexSign = *legacy
+	o := &jwt.Validation{Issuer: *iss, Audience: *aud, Subject: *sub, Leeway: *leeway}
+
+	switch {
+	case *secret != "":
+		err = t.Valid(*secret, o)
+	case *keyFile != "":
+		err = verifyFile(t, *keyFile, o)
+	default:
+		return errors.New("-secret or -key required")
+	}
+
+	if err != nil {
+		return errors.New("INVALID: " + explain(t, o, err))
+	}
+
+	fmt.Fprintln(stdout, "OK: signature and claims valid")
+	return nil
+}
+
+// verifyFile #JWKS ueber kid, einzelner Schluessel (PEM, JWK) direkt
+func verifyFile(t *jwt.JWToken, fn string, o *jwt.Validation) error {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(string(b), `"keys"`) {
+		ks, err := jwt.ParseJWKS(b)
+		if err != nil {
+			return fmt.Errorf("%s: %v", fn, err)
+		}
+
+		if err = ks.Verify(t, o); err == jwt.ErrKeyUnknown {
+			return explainKid(t, ks)
+		}
+		return err
+	}
+
+	k, err := loadKey(fn, "", "")
+	if err != nil {
+		return err
+	}
+
+	alg, _ := t.Head["alg"].(string)
+	v, err := k.Verifier(alg)
+	if err != nil {
+		return err
+	}
+
+	return t.Verify(v, o)
+}
+
+// explain #Begruendung, warum die Pruefung fehlschlaegt
+func explain(t *jwt.JWToken, o *jwt.Validation, err error) string {
+	now := time.Now()
+	alg, _ := t.Head["alg"].(string)
+
+	switch err {
+	case jwt.ErrTokenExpired:
+		exp := t.Claims.AsTime("exp")
+		return fmt.Sprintf("token expired at %s (%s, leeway %s)", exp.Format("2006-01-02 15:04:05"), relTime(exp, now), o.Leeway)
+	case jwt.ErrTokenNotYetValid:
+		nbf := t.Claims.AsTime("nbf")
+		return fmt.Sprintf("token not valid before %s (%s)", nbf.Format("2006-01-02 15:04:05"), relTime(nbf, now))
+	case jwt.ErrTokenIssuedAt:
+		iat := t.Claims.AsTime("iat")
+		return fmt.Sprintf("token issued in the future at %s (%s), check clocks or -leeway", iat.Format("2006-01-02 15:04:05"), relTime(iat, now))
+	case jwt.ErrTokenIssuer:
+		return fmt.Sprintf("issuer is %q, expected %q", t.Claims.AsString("iss"), o.Issuer)
+	case jwt.ErrTokenAudience:
+		return fmt.Sprintf("audience %q does not contain %q", t.Claims.AsStrings("aud"), o.Audience)
+	case jwt.ErrTokenSubject:
+		return fmt.Sprintf("subject is %q, expected %q", t.Claims.AsString("sub"), o.Subject)
+	case jwt.ErrAlgMismatch:
+		return fmt.Sprintf("token uses alg %s, which does not match the key", alg)
+	case jwt.ErrKeyType:
+		return fmt.Sprintf("key type cannot be used with alg %s", alg)
+	case jwt.ErrAlgUnsupported:
+		return fmt.Sprintf("alg %s not supported", alg)
+	case jwt.ErrTokenNotValid:
+		ss := strings.Split(t.RawData, ".")
+		if len(ss) == 3 && len(ss[2]) == 64 && isHex(ss[2]) && !jwt.LegacyHexSign {
+			return "signature is in legacy hex format, use -legacy"
+		}
+		return "signature does not match (wrong key or modified token)"
+	}
+
+	return err.Error()
+}
+
+func explainKid(t *jwt.JWToken, ks *jwt.KeySet) error {
+	kid, _ := t.Head["kid"].(string)
+
+	var kids []string
+	for _, k := range ks.Keys() {
+		kids = append(kids, k.Kid)
+	}
+	sort.Strings(kids)
+
+	if kid == "" {
+		return fmt.Errorf("token has no kid and no key matches (keys: %s)", strings.Join(kids, ", "))
+	}
+
+	return fmt.Errorf("kid %q not in key set (keys: %s)", kid, strings.Join(kids, ", "))
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func cmdMint(args []string) error {
+	fs := flag.NewFlagSet("mint", flag.ExitOnError)
+	secret := fs.String("secret", "", "HMAC secret")
+	keyFile := fs.String("key", "", "PEM or JWK file with private key")
+	alg := fs.String("alg", "", "algorithm, default HS256 or from key")
+	kid := fs.String("kid", "", "key id")
+	claims := fs.String("claims", "", "JSON file with claims")
+	exp := fs.Duration("exp", 15*time.Minute, "expiry from now, 0: without exp")
+	enc := fs.String("enc", "", "oct JWK for encryption (JWE)")
+	fs.Parse(args)
+
+	t := jwt.New()
+	if *claims != "" {
+		b, err := ioutil.ReadFile(*claims)
+		if err != nil {
+			return err
+		}
+
+		m := map[string]interface{}{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return fmt.Errorf("%s: %v", *claims, err)
+		}
+		t.Claims.Assign(&m)
+	}
+
+	// ab jetzt, nicht ab einem iat aus der Claims-Datei
+	if *exp > 0 {
+		t.Claims["exp"] = time.Now().Add(*exp).UTC().Unix()
+	}
+
+	var (
+		sg  jwt.Signer
+		err error
+	)
+
+	switch {
+	case *secret != "":
+		a := *alg
+		if a == "" {
+			a = "HS256"
+		}
+		sg, err = jwt.NewSigner(a, *secret)
+	case *keyFile != "":
+		var k *jwt.JWK
+		if k, err = loadKey(*keyFile, *kid, *alg); err == nil {
+			sg, err = k.Signer()
+		}
+	default:
+		return errors.New("-secret or -key required")
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if *kid != "" {
+		t.Head["kid"] = *kid
+	}
+
+	var s string
+	if *enc != "" {
+		var ek *jwt.JWK
+		if ek, err = loadKey(*enc, "", ""); err == nil {
+			s, err = t.SignEncrypt(sg, "", ek)
+		}
+	} else {
+		s, err = t.Sign(sg)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(stdout, s)
+	return nil
+}
+
+// loadKey #PEM oder JWK, kid/alg ueberschreiben leere Werte
+func loadKey(fn string, kid string, alg string) (*jwt.JWK, error) {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	var k *jwt.JWK
+	if strings.Contains(string(b), "-----BEGIN") {
+		key, err := jwt.ParsePEMKey(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fn, err)
+		}
+
+		if k, err = jwt.NewJWK(key, kid, alg); err != nil {
+			return nil, fmt.Errorf("%s: %v", fn, err)
+		}
+		return k, nil
+	}
+
+	if k, err = jwt.ParseJWK(b); err != nil {
+		return nil, fmt.Errorf("%s: %v", fn, err)
+	}
+
+	if k.Kid == "" {
+		k.Kid = kid
+	}
+
+	if k.Alg == "" {
+		k.Alg = alg
+	}
+
+	return k, nil
+}
+
+// loadKeySet #PEM, JWK oder JWKS
+func loadKeySet(fn string) (*jwt.KeySet, error) {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.Contains(string(b), `"keys"`) {
+		ks, err := jwt.ParseJWKS(b)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", fn, err)
+		}
+		return ks, nil
+	}
+
+	k, err := loadKey(fn, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.NewKeySet(k), nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/waldurbas/got/jwt"
+)
+
+// run #jwtool mit args, liefert die Ausgabe
+func run(args ...string) (string, error) {
+	var buf bytes.Buffer
+	old := stdout
+	stdout = &buf
+	defer func() { stdout = old }()
+
+	var err error
+	switch args[0] {
+	case "decode":
+		err = cmdDecode(args[1:])
+	case "verify":
+		err = cmdVerify(args[1:])
+	case "mint":
+		err = cmdMint(args[1:])
+	}
+
+	return buf.String(), err
+}
+
+func token(cl jwt.TMap) string {
+	x := jwt.New()
+	x.Claims = cl
+	return x.Encode("geheim")
+}
+
+func Test_Jwtool(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "claims.json")
+	ioutil.WriteFile(fn, []byte(`{"sub":"wu","aud":"web","iat":1000}`), 0666)
+
+	minted, err := run("mint", "-secret", "geheim", "-claims", fn, "-exp", "15m")
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+	minted = strings.TrimSpace(minted)
+
+	now := time.Now().Unix()
+	var dtest = []struct {
+		args []string
+		want string // in Ausgabe bzw. Fehler
+		ok   bool
+	}{
+		// mint: exp ab jetzt, nicht ab iat der Claims-Datei
+		{[]string{"verify", "-secret", "geheim", minted}, "OK: signature and claims valid", true},
+		{[]string{"verify", "-secret", "geheim", "-aud", "web", "-sub", "wu", minted}, "OK", true},
+		{[]string{"verify", "-secret", "falsch", minted}, "signature does not match", false},
+		{[]string{"verify", "-secret", "geheim", "-aud", "admin", minted}, `does not contain "admin"`, false},
+		{[]string{"verify", "-secret", "geheim", "-iss", "got", minted}, `expected "got"`, false},
+		{[]string{"verify", "-secret", "geheim", token(jwt.TMap{"exp": now - 60})}, "token expired at", false},
+		{[]string{"verify", "-secret", "geheim", token(jwt.TMap{"nbf": now + 600})}, "token not valid before", false},
+		{[]string{"verify", "-secret", "geheim", "Bearer " + token(jwt.TMap{"sub": "x"})}, "OK", true},
+		{[]string{"verify", "-secret", "geheim", "a.b"}, "parts", false},
+
+		// decode ohne zusaetzliches iat
+		{[]string{"decode", token(jwt.TMap{"sub": "x"})}, `"sub": "x"`, true},
+		{[]string{"decode", minted}, `"aud": "web"`, true},
+		{[]string{"decode", "eyJhbGciOiJYWDk5In0.eyJzdWIiOiIxIn0.c2ln"}, `"alg": "XX99"`, true},
+		{[]string{"decode", "eyJ.%%%.x"}, "header", false},
+	}
+
+	for i, tt := range dtest {
+		out, err := run(tt.args...)
+		if (err == nil) != tt.ok {
+			t.Errorf("%d %v: %v", i, tt.args[:len(tt.args)-1], err)
+			continue
+		}
+
+		if err != nil {
+			out = err.Error()
+		}
+		if !strings.Contains(out, tt.want) {
+			t.Errorf("%d %v: %q fehlt in %q", i, tt.args[:len(tt.args)-1], tt.want, out)
+		}
+	}
+
+	if out, _ := run("decode", token(jwt.TMap{"sub": "x"})); strings.Contains(out, "iat") {
+		t.Errorf("decode: iat ergaenzt: %s", out)
+	}
+
+	d, _ := decodeRaw(minted, "")
+	if exp := d.Claims.AsInt64("exp"); exp < now+14*60 || exp > now+16*60 {
+		t.Errorf("mint -exp: exp=%d, now=%d", exp, now)
+	}
+}
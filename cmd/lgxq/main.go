@@ -0,0 +1,380 @@
+package main
+
+// ----------------------------------------------------------------------------------
+// lgxq (https://github.com/waldurbas/got): query and tail lgx daily log files
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+//
+// lgxq -dir /usr/firma/log -pfx myprog -from "2026-10-18 22:00" -level error -grep backend -C 2
+// lgxq -dir /usr/firma/log -pfx myprog -f -re "timeout|refused"
+//
+// Logfiles: {dir}/{YYYY}/{MM}/{pfx}{YYYYMMDD}.log, auch als .log.gz
+
+import (
+	"bufio"
+	"compress/gzip"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/waldurbas/got/lgx"
+)
+
+const tsLayout = "2006-01-02 15:04:05"
+
+// query #Filter und Ausgabe
+type query struct {
+	from   time.Time
+	to     time.Time
+	minLvl lgx.Level
+	text   string
+	icase  bool
+	re     *regexp.Regexp
+
+	// Zustand der aktuellen Zeile, Folgezeilen ohne Zeit erben Zeit und Level
+	t   time.Time
+	lvl lgx.Level
+
+	// Kontext
+	ctx     int
+	before  []string
+	after   int
+	n       int64
+	last    int64
+	printed bool
+	w       *bufio.Writer
+}
+
+func main() {
+	fDir := flag.String("dir", os.Getenv("LOGDIR"), "LogDir (default $LOGDIR)")
+	fPfx := flag.String("pfx", "", "Programm-Prefix der Logfiles")
+	fFrom := flag.String("from", "", "ab: YYYY-MM-DD [HH:MM[:SS]], HH:MM oder Dauer (2h)")
+	fTo := flag.String("to", "", "bis: wie -from, default jetzt")
+	fLevel := flag.String("level", "", "min. Level: debug, info, error, fatal")
+	fGrep := flag.String("grep", "", "Text")
+	fIcase := flag.Bool("i", false, "Gross-/Kleinschreibung ignorieren")
+	fRe := flag.String("re", "", "regulaerer Ausdruck")
+	fCtx := flag.Int("C", 0, "Kontextzeilen vor und nach einem Treffer")
+	fFollow := flag.Bool("f", false, "aktuelle Datei verfolgen (tail -f), auch ueber Mitternacht")
+	flag.Parse()
+
+	if *fDir == "" || *fPfx == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	now := time.Now()
+	q := &query{
+		text:  *fGrep,
+		icase: *fIcase,
+		ctx:   *fCtx,
+		w:     bufio.NewWriter(os.Stdout),
+	}
+	defer q.w.Flush()
+
+	var err error
+	if q.from, err = parseTime(*fFrom, now, dayStart(now)); err != nil {
+		fatal(err)
+	}
+
+	if q.to, err = parseTime(*fTo, now, now); err != nil {
+		fatal(err)
+	}
+
+	if *fFollow {
+		q.to = time.Time{}
+	}
+
+	if *fLevel != "" {
+		if q.minLvl = parseLevel("[" + strings.ToUpper(*fLevel) + "]"); q.minLvl == lgx.LevelNone {
+			fatal(fmt.Errorf("unknown level: %s", *fLevel))
+		}
+	}
+
+	if q.icase {
+		q.text = strings.ToLower(q.text)
+	}
+
+	if *fRe != "" {
+		expr := *fRe
+		if q.icase {
+			expr = "(?i)" + expr
+		}
+		if q.re, err = regexp.Compile(expr); err != nil {
+			fatal(err)
+		}
+	}
+
+	last := dayStart(now)
+	if !*fFollow {
+		last = dayStart(q.to).AddDate(0, 0, 1)
+	}
+
+	for d := dayStart(q.from); d.Before(last); d = d.AddDate(0, 0, 1) {
+		if err := q.scanDay(*fDir, *fPfx, d); err != nil {
+			fatal(err)
+		}
+	}
+
+	if *fFollow {
+		q.w.Flush()
+		q.follow(*fDir, *fPfx, dayStart(now), *fFrom == "")
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "lgxq:", err)
+	os.Exit(1)
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// parseTime #YYYY-MM-DD [HH:MM[:SS]], HH:MM (heute) oder Dauer vor now
+func parseTime(s string, now time.Time, def time.Time) (time.Time, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+
+	for _, l := range []string{tsLayout, "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+
+	for _, l := range []string{"15:04:05", "15:04"} {
+		if t, err := time.ParseInLocation(l, s, time.Local); err == nil {
+			return dayStart(now).Add(time.Duration(t.Hour())*time.Hour +
+				time.Duration(t.Minute())*time.Minute + time.Duration(t.Second())*time.Second), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time: %s", s)
+}
+
+// parseLevel #[ERROR] am Zeilenanfang (nach Zeit und LinePfx)
+func parseLevel(s string) lgx.Level {
+	ix := strings.IndexByte(s, '[')
+	if ix < 0 || ix > 8 {
+		return lgx.LevelNone
+	}
+
+	s = s[ix+1:]
+	ix = strings.IndexByte(s, ']')
+	if ix < 0 {
+		return lgx.LevelNone
+	}
+
+	for _, l := range []lgx.Level{lgx.LevelDebug, lgx.LevelInfo, lgx.LevelError, lgx.LevelFatal} {
+		if s[:ix] == l.String() {
+			return l
+		}
+	}
+
+	return lgx.LevelNone
+}
+
+// openDay #.log oder .log.gz
+func openDay(dir, pfx string, d time.Time) (io.ReadCloser, error) {
+	fn := lgx.DayLogFile(dir, pfx, d)
+
+	f, err := os.Open(fn)
+	if err == nil {
+		return f, nil
+	}
+
+	gf, e := os.Open(fn + ".gz")
+	if e != nil {
+		return nil, err
+	}
+
+	zr, e := gzip.NewReader(gf)
+	if e != nil {
+		gf.Close()
+		return nil, e
+	}
+
+	return &gzFile{zr, gf}, nil
+}
+
+type gzFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g *gzFile) Close() error {
+	g.Reader.Close()
+	return g.f.Close()
+}
+
+func (q *query) scanDay(dir, pfx string, d time.Time) error {
+	r, err := openDay(dir, pfx, d)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer r.Close()
+
+	q.t = d
+	q.lvl = lgx.LevelNone
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		q.line(sc.Text())
+	}
+
+	return sc.Err()
+}
+
+// follow #wie tail -f, wechselt nach Mitternacht auf die neue Tagesdatei
+func (q *query) follow(dir, pfx string, d time.Time, atEnd bool) {
+	q.t = d
+	q.lvl = lgx.LevelNone
+
+	for {
+		f, err := os.Open(lgx.DayLogFile(dir, pfx, d))
+		if err != nil {
+			time.Sleep(time.Second)
+			if today := dayStart(time.Now()); today.After(d) {
+				d = today
+				atEnd = false
+			}
+			continue
+		}
+
+		var pos int64
+		if atEnd {
+			pos, _ = f.Seek(0, io.SeekEnd)
+			atEnd = false
+		}
+
+		rd := bufio.NewReader(f)
+		partial := ""
+		for {
+			s, err := rd.ReadString('\n')
+			pos += int64(len(s))
+			if err == nil {
+				q.line(strings.TrimRight(partial+s, "\r\n"))
+				partial = ""
+				continue
+			}
+			partial += s
+			q.w.Flush()
+
+			if today := dayStart(time.Now()); today.After(d) {
+				if partial != "" {
+					q.line(partial)
+				}
+				q.w.Flush()
+				d = today
+				break
+			}
+
+			time.Sleep(500 * time.Millisecond)
+
+			// Datei wurde abgeschnitten
+			if fi, e := f.Stat(); e == nil && fi.Size() < pos {
+				f.Seek(0, io.SeekStart)
+				rd.Reset(f)
+				pos = 0
+				partial = ""
+			}
+		}
+
+		f.Close()
+	}
+}
+
+func (q *query) line(s string) {
+	if len(s) >= len(tsLayout) {
+		if t, err := time.ParseInLocation(tsLayout, s[:len(tsLayout)], time.Local); err == nil {
+			q.t = t
+			q.lvl = parseLevel(s[len(tsLayout):])
+		}
+	}
+
+	q.add(s, q.match(s))
+}
+
+func (q *query) match(s string) bool {
+	if q.t.Before(q.from) || (!q.to.IsZero() && q.t.After(q.to)) {
+		return false
+	}
+
+	if q.minLvl != lgx.LevelNone && q.lvl < q.minLvl {
+		return false
+	}
+
+	if q.text != "" {
+		x := s
+		if q.icase {
+			x = strings.ToLower(x)
+		}
+		if !strings.Contains(x, q.text) {
+			return false
+		}
+	}
+
+	if q.re != nil && !q.re.MatchString(s) {
+		return false
+	}
+
+	return true
+}
+
+// add #Ausgabe mit Kontextzeilen wie grep -C
+func (q *query) add(s string, match bool) {
+	q.n++
+
+	if match {
+		if q.ctx > 0 && q.printed && q.n-int64(len(q.before)) > q.last+1 {
+			fmt.Fprintln(q.w, "--")
+		}
+
+		for _, b := range q.before {
+			fmt.Fprintln(q.w, b)
+		}
+		q.before = q.before[:0]
+
+		fmt.Fprintln(q.w, s)
+		q.last = q.n
+		q.after = q.ctx
+		q.printed = true
+		return
+	}
+
+	if q.after > 0 {
+		fmt.Fprintln(q.w, s)
+		q.last = q.n
+		q.after--
+		return
+	}
+
+	if q.ctx > 0 {
+		q.before = append(q.before, s)
+		if len(q.before) > q.ctx {
+			q.before = q.before[1:]
+		}
+	}
+}
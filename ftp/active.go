@@ -0,0 +1,201 @@
+package ftp
+
+// ----------------------------------------------------------------------------------
+// active.go (https://github.com/waldurbas/got)
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) accept nur vom Server, ModeAuto aktiv nur ohne EPSV/PASV oder bei Dial-Fehler
+// 2026.10.19 (wu) Init: aktiver Modus mit EPRT/PORT, ModeAuto/ModePassive/ModeActive
+//-----------------------------------------------------------------------------------
+//
+// Im aktiven Modus lauscht der Client und der Server verbindet sich.
+// Hinter NAT: ActiveAddr auf die externe IP setzen und den Port-Bereich
+// (ActivePortMin..ActivePortMax) in der Firewall freigeben.
+//
+//	f.Mode = ftp.ModeActive
+//	f.ActiveAddr = "203.0.113.7"
+//	f.ActivePortMin, f.ActivePortMax = 50000, 50100
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Mode #Art der Datenverbindung
+type Mode int
+
+const (
+	// ModeAuto #passiv (EPSV, PASV), wenn das scheitert aktiv
+	ModeAuto Mode = iota
+	// ModePassive #nur EPSV/PASV
+	ModePassive
+	// ModeActive #nur EPRT/PORT
+	ModeActive
+)
+
+// AcceptTimeout #Warten auf die Datenverbindung des Servers, wenn Ftp.Timeout 0 ist
+var AcceptTimeout = 30 * time.Second
+
+// dataCon #passiv: bereits verbunden, aktiv: Listener bis der Server verbindet
+type dataCon struct {
+	conn    net.Conn
+	ln      net.Listener
+	peer    net.IP // aktiv: nur Verbindungen vom Server der Steuerverbindung
+	timeout time.Duration
+}
+
+func (d *dataCon) accept() (net.Conn, error) {
+	if d.conn != nil {
+		return d.conn, nil
+	}
+	defer d.ln.Close()
+
+	d.ln.(*net.TCPListener).SetDeadline(time.Now().Add(d.timeout))
+	for {
+		conn, err := d.ln.Accept()
+		if err != nil {
+			return nil, err
+		}
+
+		if ra, ok := conn.RemoteAddr().(*net.TCPAddr); ok && ra.IP.Equal(d.peer) {
+			return conn, nil
+		}
+
+		// fremder Rechner (Port-Diebstahl): verwerfen, weiter warten
+		conn.Close()
+	}
+}
+
+func (d *dataCon) close() {
+	if d.conn != nil {
+		d.conn.Close()
+	}
+	if d.ln != nil {
+		d.ln.Close()
+	}
+}
+
+// openData #Datenverbindung je nach Mode vorbereiten
+func (f *Ftp) openData() (*dataCon, error) {
+	if f.Mode == ModeActive || (f.Mode == ModeAuto && f.skipPasv) {
+		return f.active()
+	}
+
+	conn, err := f.newFtpCon()
+	if err == nil {
+		return &dataCon{conn: conn}, nil
+	}
+
+	if f.Mode == ModePassive || !noPassive(err) {
+		return nil, err
+	}
+
+	// passiv nicht moeglich (kein EPSV/PASV, Adresse nicht erreichbar)
+	f.skipPasv = true
+	return f.active()
+}
+
+// noPassive #Server lehnt EPSV/PASV ab oder die Adresse ist nicht erreichbar;
+// Fehler der Steuerverbindung und 4xx fuehren nicht zum aktiven Modus
+func noPassive(err error) bool {
+	var te *textproto.Error
+	if errors.As(err, &te) {
+		return te.Code == 500 || te.Code == 502 || te.Code == 504
+	}
+
+	var oe *net.OpError
+	return errors.As(err, &oe) && oe.Op == "dial"
+}
+
+// active #lokal lauschen und dem Server die Adresse mit EPRT bzw. PORT mitteilen
+func (f *Ftp) active() (*dataCon, error) {
+	local, ok := f.ctl.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return nil, errors.New("active mode: no tcp connection")
+	}
+
+	ln, err := f.listen(local.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	ip := local.IP
+	if f.ActiveAddr != "" {
+		if ip = net.ParseIP(f.ActiveAddr); ip == nil {
+			ln.Close()
+			return nil, fmt.Errorf("active mode: invalid address %q", f.ActiveAddr)
+		}
+	}
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	if err = f.eprt(ip, port); err != nil {
+		ln.Close()
+		return nil, err
+	}
+
+	timeout := AcceptTimeout
+	if f.Timeout > 0 {
+		timeout = time.Duration(f.Timeout) * time.Second
+	}
+
+	return &dataCon{ln: ln, peer: net.ParseIP(f.Host), timeout: timeout}, nil
+}
+
+// listen #Port aus ActivePortMin..ActivePortMax, sonst beliebig
+func (f *Ftp) listen(ip net.IP) (net.Listener, error) {
+	if f.ActivePortMin <= 0 || f.ActivePortMax < f.ActivePortMin {
+		return net.Listen("tcp", net.JoinHostPort(ip.String(), "0"))
+	}
+
+	n := f.ActivePortMax - f.ActivePortMin + 1
+	start := rand.Intn(n)
+
+	var err error
+	for i := 0; i < n; i++ {
+		port := f.ActivePortMin + (start+i)%n
+
+		var ln net.Listener
+		if ln, err = net.Listen("tcp", net.JoinHostPort(ip.String(), strconv.Itoa(port))); err == nil {
+			return ln, nil
+		}
+	}
+
+	return nil, fmt.Errorf("active mode: no free port in %d-%d: %v", f.ActivePortMin, f.ActivePortMax, err)
+}
+
+// eprt #EPRT |1|ip|port| (RFC 2428), bei 500/502 fuer IPv4: PORT h1,h2,h3,h4,p1,p2
+func (f *Ftp) eprt(ip net.IP, port int) error {
+	ip4 := ip.To4()
+
+	if !f.skipEPRT || ip4 == nil {
+		af := "1"
+		if ip4 == nil {
+			af = "2"
+		}
+
+		_, _, err := f.cmd(200, "EPRT |%s|%s|%d|", af, ip.String(), port)
+		e, ok := err.(*textproto.Error)
+		if err == nil || ip4 == nil || !ok || (e.Code != 500 && e.Code != 502) {
+			return err
+		}
+
+		f.skipEPRT = true
+	}
+
+	hs := strings.Replace(ip4.String(), ".", ",", -1)
+	_, _, err := f.cmd(200, "PORT %s,%s,%s", hs, strconv.Itoa(port/256), strconv.Itoa(port%256))
+	return err
+}
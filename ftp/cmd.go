@@ -11,11 +11,16 @@ package ftp
 // ----------------------------------------------------------------------------------
 // HISTORY
 //-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) cmdDataCon: Abschlussantwort lesen, wenn keine Datenverbindung kommt
+// 2026.10.19 (wu) cmdDataCon: aktiver Modus
+// 2026.10.19 (wu) TLS fuer Datenverbindungen
+// 2026.10.19 (wu) RetrFrom, Stor, StorFrom, Append
 // 2020.10.03 (wu) Init
 //-----------------------------------------------------------------------------------
 
 import (
 	"errors"
+	"io"
 	"net"
 	"net/textproto"
 	"strconv"
@@ -24,6 +29,7 @@ import (
 )
 
 func (f *Ftp) cmd(expectCode int, format string, args ...interface{}) (int, string, error) {
+	f.deadline()
 	_, err := f.con.Cmd(format, args...)
 	if err != nil {
 		return 0, "", err
@@ -128,7 +134,12 @@ func (f *Ftp) pasv() (host string, port int, err error) {
 
 // Retr #
 func (f *Ftp) Retr(path string) (*FResponse, error) {
-	conn, err := f.cmdDataCon(0, "RETR %s", path)
+	return f.RetrFrom(path, 0)
+}
+
+// RetrFrom #ab offset (REST)
+func (f *Ftp) RetrFrom(path string, offset uint64) (*FResponse, error) {
+	conn, err := f.cmdDataCon(offset, "RETR %s", path)
 	if err != nil {
 		return nil, err
 	}
@@ -136,6 +147,42 @@ func (f *Ftp) Retr(path string) (*FResponse, error) {
 	return &FResponse{con: conn, ftp: f}, nil
 }
 
+// Stor #r als path speichern, eine vorhandene Datei wird ueberschrieben
+func (f *Ftp) Stor(path string, r io.Reader) error {
+	return f.StorFrom(path, r, 0)
+}
+
+// StorFrom #r ab offset in path schreiben (REST + STOR), r muss bereits bei offset stehen
+func (f *Ftp) StorFrom(path string, r io.Reader, offset uint64) error {
+	return f.upload(offset, r, "STOR %s", path)
+}
+
+// Append #r an path anhaengen (APPE), path wird angelegt wenn nicht vorhanden
+func (f *Ftp) Append(path string, r io.Reader) error {
+	return f.upload(0, r, "APPE %s", path)
+}
+
+func (f *Ftp) upload(offset uint64, r io.Reader, format string, args ...interface{}) error {
+	conn, err := f.cmdDataCon(offset, format, args...)
+	if err != nil {
+		return err
+	}
+
+	w := &FResponse{con: conn, ftp: f}
+	_, err = io.Copy(w, r)
+
+	// 226 (oder 250) erst nach dem Schliessen der Datenverbindung
+	conn.Close()
+	f.deadline()
+	_, _, err2 := f.con.ReadResponse(2)
+
+	if err != nil {
+		return err
+	}
+
+	return err2
+}
+
 func (f *Ftp) getPortNumber() (string, int, error) {
 	if !f.skipEPSV {
 		if port, err := f.epsv(); err == nil {
@@ -169,7 +216,7 @@ func (f *Ftp) newFtpCon() (net.Conn, error) {
 }
 
 func (f *Ftp) cmdDataCon(offset uint64, format string, args ...interface{}) (net.Conn, error) {
-	conn, err := f.newFtpCon()
+	d, err := f.openData()
 	if err != nil {
 		return nil, err
 	}
@@ -178,28 +225,64 @@ func (f *Ftp) cmdDataCon(offset uint64, format string, args ...interface{}) (net
 		// Pending
 		_, _, err := f.cmd(350, "REST %d", offset)
 		if err != nil {
-			conn.Close()
+			d.close()
 			return nil, err
 		}
 	}
 
+	f.deadline()
 	_, err = f.con.Cmd(format, args...)
 	if err != nil {
-		conn.Close()
+		d.close()
 		return nil, err
 	}
 
 	code, msg, err := f.con.ReadResponse(-1)
 	if err != nil {
-		conn.Close()
+		d.close()
 		return nil, err
 	}
 
 	// 125: already open; 150 already to send
 	if code != 125 && code != 150 {
-		conn.Close()
+		d.close()
 		return nil, &textproto.Error{Code: code, Msg: msg}
 	}
 
+	// aktiv: der Server verbindet sich jetzt
+	conn, err := d.accept()
+	if err != nil {
+		return nil, f.noData(d, err)
+	}
+
+	if f.tlsCfg != nil {
+		return f.dataHandshake(conn)
+	}
+
 	return conn, nil
 }
+
+// noData #nach 125/150 kam keine Datenverbindung: Abschlussantwort des Servers (z.B. 425)
+// lesen, sonst erhaelt sie der naechste Befehl; ohne Antwort Steuerverbindung schliessen
+func (f *Ftp) noData(d *dataCon, err error) error {
+	d.close()
+
+	if f.ctl != nil {
+		f.ctl.SetDeadline(time.Now().Add(d.timeout))
+		if f.Timeout <= 0 {
+			defer f.ctl.SetDeadline(time.Time{})
+		}
+	}
+
+	code, msg, err2 := f.con.ReadResponse(-1)
+	if err2 != nil {
+		f.con.Close()
+		return err
+	}
+
+	if code >= 400 {
+		return &textproto.Error{Code: code, Msg: msg}
+	}
+
+	return err
+}
@@ -11,6 +11,8 @@ package ftp
 // ----------------------------------------------------------------------------------
 // HISTORY
 //-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) LIST: Unix (ls -l), DOS/IIS, EPLF
+// 2026.10.19 (wu) leere Dateien nicht mehr ignorieren (siehe ListFiles)
 // 2020.10.03 (wu) Init
 //-----------------------------------------------------------------------------------
 
@@ -25,8 +27,9 @@ import (
 type FileInfo struct {
 	Name    string
 	Size    int64
-	Type    string
+	Type    string // F: Datei, D: Verzeichnis, L: Symlink (nur LIST)
 	ModTime time.Time
+	Link    string // Ziel des Symlinks
 }
 
 // STime #
@@ -39,6 +42,11 @@ func (f *FileInfo) IsDir() bool {
 	return f.Type == "D"
 }
 
+// IsLink #
+func (f *FileInfo) IsLink() bool {
+	return f.Type == "L"
+}
+
 //modify=20190815120400;perm=adfrw;size=637616;type=file;unique=FE00UD6A921A8;UNIX.group=100;UNIX.mode=0644;UNIX.owner=1200; Differenz_20200_fileliste.txt
 //modify=20190815120400;perm=adfrw;size=475466;type=file;unique=FE00UD6A921A9;UNIX.group=100;UNIX.mode=0644;UNIX.owner=1200; Differenz_80100_fileliste.txt
 //modify=20190922224800;perm=flcdmpe;type=dir;unique=FE00U473F40FE;UNIX.group=0;UNIX.mode=0777;UNIX.owner=0; 4030457000007
@@ -66,9 +74,6 @@ func parseEntry(e string) *FileInfo {
 	if typ == "file" {
 		typ = "F"
 		iSize, _ = strconv.ParseInt(m["size"], 10, 64)
-		if iSize < 1 {
-			return nil
-		}
 	} else if typ == "dir" {
 		typ = "D"
 	} else {
@@ -89,3 +94,196 @@ func parseEntry(e string) *FileInfo {
 
 	return f
 }
+
+// parseList #eine Zeile von LIST, Format wird je Zeile erkannt
+func parseList(e string, now time.Time) *FileInfo {
+	var fi *FileInfo
+
+	switch {
+	case strings.HasPrefix(e, "+"):
+		fi = parseEPLF(e)
+	case len(e) > 0 && e[0] >= '0' && e[0] <= '9':
+		fi = parseDOS(e)
+	default:
+		fi = parseUnix(e, now)
+	}
+
+	if fi == nil || fi.Name == "" || fi.Name == "." || fi.Name == ".." {
+		return nil
+	}
+
+	return fi
+}
+
+// fieldsPos #wie strings.Fields, dazu die Startposition jedes Feldes
+func fieldsPos(s string) ([]string, []int) {
+	var (
+		ff  []string
+		pos []int
+	)
+
+	start := -1
+	for i := 0; i <= len(s); i++ {
+		if i == len(s) || s[i] == ' ' || s[i] == '\t' {
+			if start >= 0 {
+				ff = append(ff, s[start:i])
+				pos = append(pos, start)
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+
+	return ff, pos
+}
+
+var months = map[string]time.Month{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+//drwxr-xr-x   2 ftp      ftp          4096 Mar  1 10:20 out
+//-rw-r--r--   1 ftp      ftp        637616 Aug 15  2019 Differenz_20200_fileliste.txt
+//lrwxrwxrwx   1 ftp      ftp            11 Mar  1 10:20 aktuell -> out/2026.txt
+//-rw-r--r--   1 owner            1234 Mar  1 10:20 ohne group.txt
+func parseUnix(e string, now time.Time) *FileInfo {
+	ff, pos := fieldsPos(e)
+	if len(ff) < 8 || len(ff[0]) < 10 {
+		return nil
+	}
+
+	var typ string
+	switch ff[0][0] {
+	case '-':
+		typ = "F"
+	case 'd':
+		typ = "D"
+	case 'l':
+		typ = "L"
+	default:
+		return nil
+	}
+
+	// Monat, Tag, Zeit oder Jahr; davor die Groesse
+	for i := 4; i+3 < len(ff); i++ {
+		mon, ok := months[strings.ToLower(ff[i])]
+		if !ok {
+			continue
+		}
+
+		size, err1 := strconv.ParseInt(ff[i-1], 10, 64)
+		day, err2 := strconv.Atoi(ff[i+1])
+		if err1 != nil || err2 != nil {
+			continue
+		}
+
+		var mtime time.Time
+		if hm := strings.SplitN(ff[i+2], ":", 2); len(hm) == 2 {
+			// innerhalb der letzten 6 Monate: ohne Jahr
+			h, _ := strconv.Atoi(hm[0])
+			m, _ := strconv.Atoi(hm[1])
+			mtime = time.Date(now.Year(), mon, day, h, m, 0, 0, time.UTC)
+			if mtime.After(now.Add(24 * time.Hour)) {
+				mtime = mtime.AddDate(-1, 0, 0)
+			}
+		} else {
+			y, err := strconv.Atoi(ff[i+2])
+			if err != nil {
+				continue
+			}
+			mtime = time.Date(y, mon, day, 0, 0, 0, 0, time.UTC)
+		}
+
+		f := &FileInfo{
+			Name:    e[pos[i+3]:],
+			Type:    typ,
+			Size:    size,
+			ModTime: mtime,
+		}
+
+		if typ == "L" {
+			if ss := strings.SplitN(f.Name, " -> ", 2); len(ss) == 2 {
+				f.Name, f.Link = ss[0], ss[1]
+			}
+		} else if typ == "D" {
+			f.Size = 0
+		}
+
+		return f
+	}
+
+	return nil
+}
+
+//03-01-26  10:20AM       <DIR>          out
+//03-01-26  10:20AM                 1234 Differenz_20200_fileliste.txt
+//03-01-2026  22:05                 1234 iis mit 24h.txt
+func parseDOS(e string) *FileInfo {
+	ff, pos := fieldsPos(e)
+	if len(ff) < 4 {
+		return nil
+	}
+
+	var mtime time.Time
+	ok := false
+	for _, layout := range []string{"01-02-06 03:04PM", "01-02-2006 03:04PM", "01-02-06 15:04", "01-02-2006 15:04"} {
+		t, err := time.ParseInLocation(layout, ff[0]+" "+strings.ToUpper(ff[1]), time.UTC)
+		if err == nil {
+			mtime, ok = t, true
+			break
+		}
+	}
+	if !ok {
+		return nil
+	}
+
+	f := &FileInfo{Name: e[pos[3]:], ModTime: mtime}
+	if strings.EqualFold(ff[2], "<DIR>") {
+		f.Type = "D"
+		return f
+	}
+
+	size, err := strconv.ParseInt(ff[2], 10, 64)
+	if err != nil {
+		return nil
+	}
+	f.Type = "F"
+	f.Size = size
+
+	return f
+}
+
+// EPLF (https://cr.yp.to/ftp/list/eplf.html)
+//+i8388621.48594,m825718503,r,s280,\tdjb.html
+//+i8388621.50690,m824255907,/,\t514
+func parseEPLF(e string) *FileInfo {
+	i := strings.IndexByte(e, '\t')
+	if i < 0 {
+		return nil
+	}
+
+	f := &FileInfo{Name: e[i+1:]}
+	for _, fact := range strings.Split(e[1:i], ",") {
+		switch {
+		case fact == "/":
+			f.Type = "D"
+		case fact == "r":
+			if f.Type == "" {
+				f.Type = "F"
+			}
+		case strings.HasPrefix(fact, "s"):
+			f.Size, _ = strconv.ParseInt(fact[1:], 10, 64)
+		case strings.HasPrefix(fact, "m"):
+			if sec, err := strconv.ParseInt(fact[1:], 10, 64); err == nil {
+				f.ModTime = time.Unix(sec, 0).UTC()
+			}
+		}
+	}
+
+	if f.Type == "" {
+		return nil
+	}
+
+	return f
+}
@@ -11,11 +11,17 @@ package ftp
 // ----------------------------------------------------------------------------------
 // HISTORY
 //-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Mode: aktiv/passiv (siehe active.go)
+// 2026.10.19 (wu) FTPS (siehe tls.go)
+// 2026.10.19 (wu) LIST statt MLSD, wenn der Server kein MLST meldet
+// 2026.10.19 (wu) list: alle Eintraege (RemoveDirAll)
+// 2026.10.19 (wu) Reconnect, Retries, Timeout auch fuer die Steuerverbindung
 // 2020.10.03 (wu) Init
 //-----------------------------------------------------------------------------------
 
 import (
 	"bufio"
+	"crypto/tls"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -28,14 +34,39 @@ import (
 // Ftp #
 type Ftp struct {
 	con      *textproto.Conn
-	Timeout  int
+	ctl      net.Conn
+	Timeout  int // Sekunden, fuer Verbindungsaufbau und jede Lese-/Schreiboperation
+	Retries  int // Wiederholungen nach Verbindungsabbruch (GetFile, PutFile)
 	Host     string
 	skipEPSV bool
+	skipMLSD bool
+	skipPasv bool
+	skipEPRT bool
 	mFeat    map[string]string
+
+	Mode          Mode   // ModeAuto, ModePassive, ModeActive
+	ActiveAddr    string // IP fuer EPRT/PORT (NAT), "": lokale Adresse der Steuerverbindung
+	ActivePortMin int    // Port-Bereich im aktiven Modus, 0: beliebig
+	ActivePortMax int
+
+	addr     string
+	user     string
+	pwd      string
+	tlsCfg   *tls.Config // nil: ohne TLS
+	implicit bool
 }
 
+// DefaultRetries #fuer neue Verbindungen
+var DefaultRetries = 3
+
 // Connect #
 func Connect(conStr string, timeout int) (*Ftp, error) {
+	return connect(conStr, func(addr string) (*Ftp, error) {
+		return DialFtp(addr, timeout)
+	})
+}
+
+func connect(conStr string, dial func(addr string) (*Ftp, error)) (*Ftp, error) {
 	//conStr := user + ":" + pwd + "@" + host
 
 	ss := strings.Split(conStr, "@")
@@ -52,7 +83,7 @@ func Connect(conStr string, timeout int) (*Ftp, error) {
 	user := ss[0]
 	pwd := ss[1]
 
-	f, err := DialFtp(host, timeout)
+	f, err := dial(host)
 
 	if err != nil {
 		return nil, err
@@ -68,28 +99,84 @@ func Connect(conStr string, timeout int) (*Ftp, error) {
 
 // DialFtp #
 func DialFtp(addr string, timeout int) (*Ftp, error) {
-	conn, err := net.DialTimeout("tcp", addr, 10*time.Second)
+	f := &Ftp{
+		Timeout: timeout,
+		Retries: DefaultRetries,
+		addr:    addr,
+	}
 
-	if err != nil {
+	if err := f.dial(); err != nil {
 		return nil, err
 	}
 
+	return f, nil
+}
+
+func (f *Ftp) dial() error {
+	conn, err := net.DialTimeout("tcp", f.addr, 10*time.Second)
+	if err != nil {
+		return err
+	}
+
 	rAddr := conn.RemoteAddr().(*net.TCPAddr)
-	var srcConn io.ReadWriteCloser = conn
 
-	f := &Ftp{
-		con:     textproto.NewConn(srcConn),
-		Host:    rAddr.IP.String(),
-		mFeat:   make(map[string]string),
-		Timeout: timeout,
+	if f.tlsCfg != nil && f.implicit {
+		tc, err := f.handshake(conn)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		conn = tc
 	}
+
+	var srcConn io.ReadWriteCloser = conn
+
+	f.ctl = conn
+	f.con = textproto.NewConn(srcConn)
+	f.Host = rAddr.IP.String()
+	f.mFeat = make(map[string]string)
+	f.skipEPSV = false
+	f.skipMLSD = false
+	f.skipPasv = false
+	f.skipEPRT = false
+
+	f.deadline()
 	_, _, err = f.con.ReadResponse(220)
 	if err != nil {
 		f.Quit()
-		return nil, err
+		return err
 	}
 
-	return f, nil
+	if f.tlsCfg != nil && !f.implicit {
+		if err = f.authTLS(); err != nil {
+			f.con.Close()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconnect #nach Verbindungsabbruch: neu verbinden, anmelden und nach dir wechseln
+func (f *Ftp) reconnect(dir string) error {
+	f.con.Close()
+
+	if err := f.dial(); err != nil {
+		return err
+	}
+
+	if err := f.Login(f.user, f.pwd); err != nil || dir == "" {
+		return err
+	}
+
+	return f.ChangeDir(dir)
+}
+
+// deadline #Timeout fuer die naechste Operation auf der Steuerverbindung
+func (f *Ftp) deadline() {
+	if f.Timeout > 0 && f.ctl != nil {
+		f.ctl.SetDeadline(time.Now().Add(time.Duration(f.Timeout) * time.Second))
+	}
 }
 
 // Quit #
@@ -100,6 +187,7 @@ func (f *Ftp) Quit() error {
 
 // Login #
 func (f *Ftp) Login(user, pwd string) error {
+	f.user, f.pwd = user, pwd
 
 	c, m, err := f.cmd(-1, "USER %s", user)
 	if err != nil {
@@ -118,6 +206,12 @@ func (f *Ftp) Login(user, pwd string) error {
 		return errors.New(m)
 	}
 
+	if f.tlsCfg != nil {
+		if err = f.protect(); err != nil {
+			return err
+		}
+	}
+
 	// check FEAT-command
 	f.feat()
 
@@ -129,9 +223,51 @@ func (f *Ftp) Login(user, pwd string) error {
 	return nil
 }
 
-// ListFiles #with MLSD
+// ListFiles #mit MLSD oder LIST, ohne leere Dateien
 func (f *Ftp) ListFiles(path string) (*[]FileInfo, error) {
-	c, err := f.cmdDataCon(0, "MLSD %s", path)
+	all, err := f.list(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var ff []FileInfo
+	for _, fi := range all {
+		if fi.IsDir() || fi.Size > 0 {
+			ff = append(ff, fi)
+		}
+	}
+
+	return &ff, nil
+}
+
+// list #MLSD wenn bei FEAT gemeldet, sonst (oder bei 500/502/504) LIST
+func (f *Ftp) list(path string) ([]FileInfo, error) {
+	if f.Supports("MLST") && !f.skipMLSD {
+		ff, err := f.listWith("MLSD", path, parseEntry)
+		if e, ok := err.(*textproto.Error); !ok || (e.Code != 500 && e.Code != 502 && e.Code != 504) {
+			return ff, err
+		}
+
+		f.skipMLSD = true
+	}
+
+	now := time.Now()
+	return f.listWith("LIST", path, func(e string) *FileInfo {
+		return parseList(e, now)
+	})
+}
+
+func (f *Ftp) listWith(cmd string, path string, parse func(e string) *FileInfo) ([]FileInfo, error) {
+	var (
+		c   net.Conn
+		err error
+	)
+
+	if path == "" {
+		c, err = f.cmdDataCon(0, cmd)
+	} else {
+		c, err = f.cmdDataCon(0, cmd+" %s", path)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +280,7 @@ func (f *Ftp) ListFiles(path string) (*[]FileInfo, error) {
 	var ff []FileInfo
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
-		fi := parseEntry(scanner.Text())
+		fi := parse(scanner.Text())
 		if fi != nil {
 			ff = append(ff, *fi)
 		}
@@ -154,23 +290,14 @@ func (f *Ftp) ListFiles(path string) (*[]FileInfo, error) {
 		return nil, err
 	}
 
-	return &ff, err
+	return ff, nil
 }
 
-// GetFile #
+// GetFile #dstFile wird ueberschrieben, nach Verbindungsabbruch wird fortgesetzt
 func (f *Ftp) GetFile(srcFile string, dstFile string) error {
-
-	r, err := f.Retr(srcFile)
-	if err != nil {
-		return err
-	}
-	defer r.Close()
-
-	buf, err := ioutil.ReadAll(r)
-	if err != nil {
+	if err := ioutil.WriteFile(dstFile, nil, 0666); err != nil {
 		return err
 	}
 
-	err = ioutil.WriteFile(dstFile, buf, 0666)
-	return err
+	return f.ResumeGetFile(srcFile, dstFile)
 }
@@ -0,0 +1,1235 @@
+package ftp_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/waldurbas/got/ftp"
+)
+
+// server #FTP-Server im Speicher fuer die Tests
+type server struct {
+	ln    net.Listener
+	mu    sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+	mtime map[string]time.Time
+	feat  []string
+	list  []string // Antwort auf LIST, nil: ls -l aus files und dirs
+	mlsd  bool     // false: MLSD mit 502 ablehnen
+	drop  int      // naechste Datenverbindung nach drop Bytes abbrechen
+	conns int      // Anzahl Steuerverbindungen
+
+	tls      *tls.Config // AUTH TLS bzw. implizit
+	implicit bool
+	reuse    bool // Datenverbindung nur mit TLS-Session der Steuerverbindung (vsftpd)
+	resumed  int  // Datenverbindungen mit Session-Reuse
+
+	nopasv  bool     // EPSV/PASV mit 502 ablehnen
+	pasvErr int      // EPSV/PASV mit diesem Code ablehnen, z.B. 421
+	noeprt  bool     // EPRT mit 502 ablehnen
+	active  []string // EPRT/PORT: Befehl und Adresse
+	rogue   bool     // nach EPRT/PORT verbindet sich zuerst ein fremder Rechner (127.0.0.2)
+	noconn  bool     // EPRT/PORT annehmen, aber nie verbinden (425 nach 150)
+}
+
+func newServer(t *testing.T) *server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &server{
+		ln:    ln,
+		files: make(map[string][]byte),
+		dirs:  map[string]bool{"/": true},
+		mtime: make(map[string]time.Time),
+		mlsd:  true,
+		feat:  []string{"EPSV", "PASV", "REST STREAM", "SIZE", "MDTM", "MFMT", "MLST type*;size*;modify*;"},
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.conns++
+			s.mu.Unlock()
+			go s.serve(c)
+		}
+	}()
+
+	return s
+}
+
+func (s *server) addr() string {
+	return s.ln.Addr().String()
+}
+
+func (s *server) put(name string, b []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[path.Join("/", name)] = b
+}
+
+func (s *server) file(name string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.files[path.Join("/", name)]
+}
+
+// session #Zustand einer Steuerverbindung
+type session struct {
+	s    *server
+	c    net.Conn
+	r    *bufio.Reader
+	w    *bufio.Writer
+	prot bool
+	pasv net.Listener
+	port string // aktiv: Adresse des Clients
+	rest int
+	cwd  string
+	rnfr string
+}
+
+// path #arg relativ zu cwd
+func (x *session) path(arg string) string {
+	if strings.HasPrefix(arg, "/") {
+		return path.Clean(arg)
+	}
+
+	return path.Join(x.cwd, arg)
+}
+
+func (x *session) reply(code int, msg string) {
+	fmt.Fprintf(x.w, "%d %s\r\n", code, msg)
+	x.w.Flush()
+}
+
+// data #Datenverbindung annehmen, bei Fehler Antwort an den Client und nil
+func (x *session) data() net.Conn {
+	var (
+		dc  net.Conn
+		err error
+	)
+
+	switch {
+	case x.port != "":
+		dc, err = net.DialTimeout("tcp", x.port, 5*time.Second)
+		x.port = ""
+	case x.pasv != nil:
+		x.pasv.(*net.TCPListener).SetDeadline(time.Now().Add(5 * time.Second))
+		dc, err = x.pasv.Accept()
+		x.pasv.Close()
+		x.pasv = nil
+	default:
+		x.reply(425, "no data connection")
+		return nil
+	}
+	if err != nil {
+		x.reply(425, err.Error())
+		return nil
+	}
+
+	if x.s.tls == nil {
+		return dc
+	}
+
+	if !x.prot {
+		dc.Close()
+		x.reply(522, "data connections must be encrypted")
+		return nil
+	}
+
+	tc := tls.Server(dc, x.s.tls)
+	if err := tc.Handshake(); err != nil {
+		dc.Close()
+		x.reply(522, "TLS handshake failed")
+		return nil
+	}
+
+	x.s.mu.Lock()
+	defer x.s.mu.Unlock()
+
+	if !tc.ConnectionState().DidResume {
+		if x.s.reuse {
+			tc.Close()
+			x.reply(522, "SSL connection failed: session reuse required")
+			return nil
+		}
+	} else {
+		x.s.resumed++
+	}
+
+	return tc
+}
+
+// dropAt #Abbruch fuer diese Uebertragung, einmalig
+func (s *server) dropAt() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := s.drop
+	s.drop = 0
+	return n
+}
+
+func (s *server) serve(c net.Conn) {
+	if s.implicit {
+		c = tls.Server(c, s.tls)
+	}
+
+	x := &session{s: s, c: c, r: bufio.NewReader(c), w: bufio.NewWriter(c), cwd: "/"}
+	defer func() { x.c.Close() }()
+	x.reply(220, "ready")
+
+	for {
+		line, err := x.r.ReadString('\n')
+		if err != nil {
+			return
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		cmd, arg := line, ""
+		if i := strings.IndexByte(line, ' '); i > 0 {
+			cmd, arg = line[:i], line[i+1:]
+		}
+
+		rest := x.rest
+		x.rest = 0
+
+		switch strings.ToUpper(cmd) {
+		case "AUTH":
+			if s.tls == nil || s.implicit || strings.ToUpper(arg) != "TLS" {
+				x.reply(504, "AUTH not supported")
+				continue
+			}
+			x.reply(234, "AUTH TLS ok")
+			tc := tls.Server(x.c, s.tls)
+			if tc.Handshake() != nil {
+				return
+			}
+			x.c, x.r, x.w = tc, bufio.NewReader(tc), bufio.NewWriter(tc)
+		case "PBSZ":
+			x.reply(200, "PBSZ=0")
+		case "PROT":
+			x.prot = strings.ToUpper(arg) == "P"
+			x.reply(200, "ok")
+		case "USER":
+			x.reply(331, "password required")
+		case "PASS":
+			x.reply(230, "logged in")
+		case "FEAT":
+			fmt.Fprintf(x.w, "211-Features:\r\n")
+			for _, f := range s.feat {
+				fmt.Fprintf(x.w, " %s\r\n", f)
+			}
+			x.reply(211, "End")
+		case "TYPE":
+			x.reply(200, "ok")
+		case "EPSV", "PASV":
+			if s.nopasv {
+				x.reply(502, "not implemented")
+				continue
+			}
+			if s.pasvErr > 0 {
+				x.reply(s.pasvErr, "service not available")
+				continue
+			}
+			if x.pasv != nil {
+				x.pasv.Close()
+			}
+			if x.pasv, err = net.Listen("tcp", "127.0.0.1:0"); err != nil {
+				x.reply(425, err.Error())
+				continue
+			}
+			port := x.pasv.Addr().(*net.TCPAddr).Port
+			if strings.ToUpper(cmd) == "EPSV" {
+				x.reply(229, fmt.Sprintf("Entering Extended Passive Mode (|||%d|)", port))
+			} else {
+				x.reply(227, fmt.Sprintf("Entering Passive Mode (127,0,0,1,%d,%d)", port/256, port%256))
+			}
+		case "EPRT", "PORT":
+			x.eport(strings.ToUpper(cmd), arg)
+		case "REST":
+			x.rest, _ = strconv.Atoi(arg)
+			x.reply(350, "restarting")
+		case "SIZE":
+			s.mu.Lock()
+			b, ok := s.files[x.path(arg)]
+			s.mu.Unlock()
+			if !ok {
+				x.reply(550, "not found")
+				continue
+			}
+			x.reply(213, strconv.Itoa(len(b)))
+		case "RETR":
+			x.retr(x.path(arg), rest)
+		case "STOR", "APPE":
+			x.stor(x.path(arg), rest, strings.ToUpper(cmd) == "APPE")
+		case "MLSD":
+			if !s.mlsd {
+				x.reply(502, "not implemented")
+				continue
+			}
+			x.mlsd(x.path(arg))
+		case "LIST":
+			x.ls(x.path(arg))
+		case "QUIT":
+			x.reply(221, "bye")
+			return
+		default:
+			x.manage(strings.ToUpper(cmd), arg)
+		}
+	}
+}
+
+// eport #EPRT |1|ip|port| bzw. PORT h1,h2,h3,h4,p1,p2
+func (x *session) eport(cmd string, arg string) {
+	var host, port string
+
+	if cmd == "EPRT" {
+		if x.s.noeprt {
+			x.reply(502, "not implemented")
+			return
+		}
+		ss := strings.Split(arg, "|")
+		if len(ss) != 5 {
+			x.reply(501, "syntax error")
+			return
+		}
+		host, port = ss[2], ss[3]
+	} else {
+		ss := strings.Split(arg, ",")
+		if len(ss) != 6 {
+			x.reply(501, "syntax error")
+			return
+		}
+		p1, _ := strconv.Atoi(ss[4])
+		p2, _ := strconv.Atoi(ss[5])
+		host, port = strings.Join(ss[:4], "."), strconv.Itoa(p1*256+p2)
+	}
+
+	x.port = net.JoinHostPort(host, port)
+	x.s.mu.Lock()
+	x.s.active = append(x.s.active, cmd+" "+x.port)
+	rogue := x.s.rogue
+	if x.s.noconn {
+		x.port = "127.0.0.1:1"
+	}
+	x.s.mu.Unlock()
+
+	if rogue {
+		d := net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP("127.0.0.2")}, Timeout: 5 * time.Second}
+		if c, err := d.Dial("tcp", x.port); err == nil {
+			c.Write([]byte("fremde Daten"))
+			defer c.Close()
+		}
+	}
+
+	x.reply(200, "ok")
+}
+
+// manage #DELE, RNFR/RNTO, MKD, RMD, CWD, PWD, MDTM, MFMT
+func (x *session) manage(cmd string, arg string) {
+	s := x.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p := x.path(arg)
+	_, isFile := s.files[p]
+
+	switch cmd {
+	case "DELE":
+		if !isFile {
+			x.reply(550, "not found")
+			return
+		}
+		delete(s.files, p)
+		x.reply(250, "deleted")
+	case "RNFR":
+		if !isFile && !s.dirs[p] {
+			x.reply(550, "not found")
+			return
+		}
+		x.rnfr = p
+		x.reply(350, "ready for RNTO")
+	case "RNTO":
+		if x.rnfr == "" {
+			x.reply(503, "RNFR first")
+			return
+		}
+		if b, ok := s.files[x.rnfr]; ok {
+			delete(s.files, x.rnfr)
+			s.files[p] = b
+		} else {
+			delete(s.dirs, x.rnfr)
+			s.dirs[p] = true
+		}
+		x.rnfr = ""
+		x.reply(250, "renamed")
+	case "MKD":
+		if s.dirs[p] || isFile {
+			x.reply(550, "exists")
+			return
+		}
+		s.dirs[p] = true
+		x.reply(257, fmt.Sprintf("%q created", p))
+	case "RMD":
+		if !s.dirs[p] {
+			x.reply(550, "not found")
+			return
+		}
+		for k := range s.files {
+			if path.Dir(k) == p {
+				x.reply(550, "not empty")
+				return
+			}
+		}
+		for k := range s.dirs {
+			if k != p && path.Dir(k) == p {
+				x.reply(550, "not empty")
+				return
+			}
+		}
+		delete(s.dirs, p)
+		x.reply(250, "removed")
+	case "CWD":
+		if !s.dirs[p] {
+			x.reply(550, "not found")
+			return
+		}
+		x.cwd = p
+		x.reply(250, "ok")
+	case "PWD":
+		x.reply(257, `"`+strings.ReplaceAll(x.cwd, `"`, `""`)+`" is current directory`)
+	case "MDTM":
+		if !isFile {
+			x.reply(550, "not found")
+			return
+		}
+		x.reply(213, s.mtime[p].UTC().Format("20060102150405"))
+	case "MFMT":
+		// MFMT zeit pfad
+		ss := strings.SplitN(arg, " ", 2)
+		t, err := time.Parse("20060102150405", ss[0])
+		if err != nil || len(ss) != 2 {
+			x.reply(501, "syntax error")
+			return
+		}
+		p = x.path(ss[1])
+		if _, ok := s.files[p]; !ok {
+			x.reply(550, "not found")
+			return
+		}
+		s.mtime[p] = t
+		x.reply(213, "Modify="+ss[0]+"; "+ss[1])
+	default:
+		x.reply(502, "not implemented")
+	}
+}
+
+func (x *session) retr(name string, rest int) {
+	x.s.mu.Lock()
+	b, ok := x.s.files[name]
+	x.s.mu.Unlock()
+	if !ok {
+		x.reply(550, "not found")
+		return
+	}
+
+	x.reply(150, "opening")
+	dc := x.data()
+	if dc == nil {
+		return
+	}
+
+	b = b[rest:]
+	if n := x.s.dropAt(); n > 0 && n < len(b) {
+		dc.Write(b[:n])
+		dc.Close()
+		x.reply(426, "connection aborted")
+		return
+	}
+
+	dc.Write(b)
+	dc.Close()
+	x.reply(226, "done")
+}
+
+func (x *session) stor(name string, rest int, appe bool) {
+	x.reply(150, "opening")
+	dc := x.data()
+	if dc == nil {
+		return
+	}
+
+	var src io.Reader = dc
+	n := x.s.dropAt()
+	if n > 0 {
+		src = io.LimitReader(dc, int64(n))
+	}
+	b, _ := ioutil.ReadAll(src)
+	dc.Close()
+
+	x.s.mu.Lock()
+	old := x.s.files[name]
+	switch {
+	case appe:
+		x.s.files[name] = append(append([]byte{}, old...), b...)
+	case rest > 0 && rest <= len(old):
+		x.s.files[name] = append(append([]byte{}, old[:rest]...), b...)
+	default:
+		x.s.files[name] = b
+	}
+	x.s.mu.Unlock()
+
+	if n > 0 {
+		x.reply(426, "connection aborted")
+		return
+	}
+	x.reply(226, "done")
+}
+
+func (x *session) mlsd(dir string) {
+	x.reply(150, "opening")
+	dc := x.data()
+	if dc == nil {
+		return
+	}
+
+	x.s.mu.Lock()
+	fmt.Fprintf(dc, "modify=20260101120000;type=cdir; %s\r\n", dir)
+	for k, b := range x.s.files {
+		if path.Dir(k) == dir {
+			fmt.Fprintf(dc, "modify=20260101120000;size=%d;type=file; %s\r\n", len(b), path.Base(k))
+		}
+	}
+	for k := range x.s.dirs {
+		if k != dir && path.Dir(k) == dir {
+			fmt.Fprintf(dc, "modify=20260101120000;type=dir; %s\r\n", path.Base(k))
+		}
+	}
+	x.s.mu.Unlock()
+
+	dc.Close()
+	x.reply(226, "done")
+}
+
+func (x *session) ls(dir string) {
+	x.reply(150, "opening")
+	dc := x.data()
+	if dc == nil {
+		return
+	}
+
+	x.s.mu.Lock()
+	if x.s.list != nil {
+		for _, e := range x.s.list {
+			fmt.Fprintf(dc, "%s\r\n", e)
+		}
+	} else {
+		fmt.Fprintf(dc, "total 0\r\n")
+		for k, b := range x.s.files {
+			if path.Dir(k) == dir {
+				fmt.Fprintf(dc, "-rw-r--r--   1 ftp      ftp      %8d Jan  1  2026 %s\r\n", len(b), path.Base(k))
+			}
+		}
+		for k := range x.s.dirs {
+			if k != dir && path.Dir(k) == dir {
+				fmt.Fprintf(dc, "drwxr-xr-x   2 ftp      ftp          4096 Jan  1  2026 %s\r\n", path.Base(k))
+			}
+		}
+	}
+	x.s.mu.Unlock()
+
+	dc.Close()
+	x.reply(226, "done")
+}
+
+func dial(t *testing.T, s *server) *ftp.Ftp {
+	f, err := ftp.Connect("user:geheim@"+s.addr(), 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Quit() })
+
+	return f
+}
+
+func testData(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i * 7)
+	}
+
+	return b
+}
+
+func Test_StorRetr(t *testing.T) {
+	s := newServer(t)
+	f := dial(t, s)
+
+	if err := f.Stor("a.txt", strings.NewReader("hallo ")); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Append("a.txt", strings.NewReader("welt")); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(s.file("a.txt")); got != "hallo welt" {
+		t.Errorf("Append: %q", got)
+	}
+
+	r, err := f.RetrFrom("a.txt", 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "welt" {
+		t.Errorf("RetrFrom: %q", b)
+	}
+
+	if err := f.StorFrom("a.txt", strings.NewReader("WELT"), 6); err != nil {
+		t.Fatal(err)
+	}
+	if got := string(s.file("a.txt")); got != "hallo WELT" {
+		t.Errorf("StorFrom: %q", got)
+	}
+
+	ff, err := f.ListFiles("")
+	if err != nil || len(*ff) != 1 || (*ff)[0].Name != "a.txt" {
+		t.Errorf("ListFiles: %v %v", ff, err)
+	}
+}
+
+func Test_ResumeGet(t *testing.T) {
+	s := newServer(t)
+	f := dial(t, s)
+
+	data := testData(300000)
+	s.put("big.bin", data)
+	s.drop = 100000
+
+	dst := filepath.Join(t.TempDir(), "big.bin")
+	if err := f.GetFile("big.bin", dst); err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := ioutil.ReadFile(dst)
+	if !bytes.Equal(b, data) {
+		t.Errorf("GetFile: %d Bytes, erwartet %d", len(b), len(data))
+	}
+	if s.conns != 2 {
+		t.Errorf("Reconnect: %d Verbindungen", s.conns)
+	}
+
+	// Fortsetzen einer lokal vorhandenen Teildatei
+	ioutil.WriteFile(dst, data[:1234], 0666)
+	if err := f.ResumeGetFile("big.bin", dst); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dst); !bytes.Equal(b, data) {
+		t.Errorf("ResumeGetFile: %d Bytes", len(b))
+	}
+}
+
+func Test_ResumePut(t *testing.T) {
+	for _, feat := range []string{"REST STREAM", ""} {
+		s := newServer(t)
+		if feat == "" {
+			// ohne REST STREAM: APPE
+			s.feat = []string{"EPSV", "SIZE"}
+		}
+		f := dial(t, s)
+
+		data := testData(300000)
+		src := filepath.Join(t.TempDir(), "big.bin")
+		ioutil.WriteFile(src, data, 0666)
+
+		s.mu.Lock()
+		s.drop = 100000
+		s.mu.Unlock()
+
+		if err := f.PutFile(src, "big.bin"); err != nil {
+			t.Fatalf("%q: %v", feat, err)
+		}
+		if b := s.file("big.bin"); !bytes.Equal(b, data) {
+			t.Errorf("%q: PutFile: %d Bytes, erwartet %d", feat, len(b), len(data))
+		}
+
+		// Fortsetzen nach Teil-Upload
+		s.put("big.bin", data[:5000])
+		if err := f.ResumePutFile(src, "big.bin"); err != nil {
+			t.Fatal(err)
+		}
+		if b := s.file("big.bin"); !bytes.Equal(b, data) {
+			t.Errorf("%q: ResumePutFile: %d Bytes", feat, len(b))
+		}
+	}
+}
+
+func Test_NoRetry(t *testing.T) {
+	s := newServer(t)
+	f := dial(t, s)
+	f.Retries = 0
+
+	s.put("x", testData(300000))
+	s.drop = 1000
+
+	err := f.GetFile("x", filepath.Join(t.TempDir(), "x"))
+	if err == nil || !strings.Contains(err.Error(), "426") {
+		t.Errorf("ohne Retries: %v", err)
+	}
+
+	// 5xx wird nicht wiederholt
+	f.Retries = 3
+	if err := f.GetFile("fehlt", filepath.Join(t.TempDir(), "y")); err == nil || s.conns != 1 {
+		t.Errorf("550: %v, %d Verbindungen", err, s.conns)
+	}
+}
+
+func Test_ReconnectDir(t *testing.T) {
+	s := newServer(t)
+	f := dial(t, s)
+
+	data := testData(300000)
+	if err := f.MakeDir("/sub"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.ChangeDir("/sub"); err != nil {
+		t.Fatal(err)
+	}
+
+	s.put("sub/big.bin", data)
+	s.mu.Lock()
+	s.drop = 100000
+	s.mu.Unlock()
+
+	// relativer Pfad nach dem Reconnect
+	dst := filepath.Join(t.TempDir(), "big.bin")
+	if err := f.GetFile("big.bin", dst); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dst); !bytes.Equal(b, data) {
+		t.Errorf("GetFile: %d Bytes", len(b))
+	}
+	if dir, _ := f.CurrentDir(); dir != "/sub" || s.conns != 2 {
+		t.Errorf("Reconnect: %s, %d Verbindungen", dir, s.conns)
+	}
+
+	// Datei auf dem Server groesser als lokal: Fehler, kein Retry
+	src := filepath.Join(t.TempDir(), "klein.bin")
+	ioutil.WriteFile(src, data[:1000], 0666)
+	s.put("sub/klein.bin", data[:2000])
+	if err := f.ResumePutFile(src, "klein.bin"); err == nil || s.conns != 2 {
+		t.Errorf("ResumePutFile: %v, %d Verbindungen", err, s.conns)
+	}
+	if b := s.file("sub/klein.bin"); len(b) != 2000 {
+		t.Errorf("ResumePutFile: %d Bytes auf dem Server", len(b))
+	}
+}
+
+func Test_Manage(t *testing.T) {
+	s := newServer(t)
+	f := dial(t, s)
+
+	for _, d := range []string{"out", "out/sub", "out/sub/tief"} {
+		if err := f.MakeDir(d); err != nil {
+			t.Fatalf("MakeDir %s: %v", d, err)
+		}
+	}
+	s.put("out/a.txt", []byte("abc"))
+	s.put("out/leer.txt", nil)
+	s.put("out/sub/b.txt", []byte("b"))
+
+	if err := f.ChangeDir("out"); err != nil {
+		t.Fatal(err)
+	}
+	if d, err := f.CurrentDir(); err != nil || d != "/out" {
+		t.Errorf("CurrentDir: %q %v", d, err)
+	}
+
+	if n, err := f.Size("a.txt"); err != nil || n != 3 {
+		t.Errorf("Size: %d %v", n, err)
+	}
+
+	mt := time.Date(2026, 3, 1, 10, 20, 30, 0, time.UTC)
+	if err := f.SetModTime("a.txt", mt); err != nil {
+		t.Fatal(err)
+	}
+	if x, err := f.ModTime("a.txt"); err != nil || !x.Equal(mt) {
+		t.Errorf("ModTime: %v %v", x, err)
+	}
+
+	if err := f.Rename("a.txt", "/out/c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if s.file("out/a.txt") != nil || string(s.file("out/c.txt")) != "abc" {
+		t.Errorf("Rename")
+	}
+
+	if err := f.Delete("c.txt"); err != nil || s.file("out/c.txt") != nil {
+		t.Errorf("Delete: %v", err)
+	}
+	if err := f.Delete("c.txt"); err == nil {
+		t.Errorf("Delete: nicht vorhanden ohne Fehler")
+	}
+
+	// ListFiles ohne leere Dateien
+	if ff, err := f.ListFiles(""); err != nil || len(*ff) != 1 || !(*ff)[0].IsDir() {
+		t.Errorf("ListFiles: %v %v", ff, err)
+	}
+
+	if err := f.RemoveDir("/out/sub"); err == nil {
+		t.Errorf("RemoveDir: nicht leer ohne Fehler")
+	}
+	if err := f.ChangeDir("/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.RemoveDirAll("out"); err != nil {
+		t.Fatal(err)
+	}
+	s.mu.Lock()
+	n := len(s.files) + len(s.dirs)
+	s.mu.Unlock()
+	if n != 1 {
+		t.Errorf("RemoveDirAll: %d Eintraege uebrig", n)
+	}
+}
+
+func Test_Unsupported(t *testing.T) {
+	s := newServer(t)
+	s.feat = []string{"EPSV"}
+	f := dial(t, s)
+
+	s.put("a.txt", []byte("abc"))
+
+	_, err := f.Size("a.txt")
+	if e, ok := err.(*ftp.UnsupportedError); !ok || e.Cmd != "SIZE" {
+		t.Errorf("Size: %v", err)
+	}
+	if _, err := f.ModTime("a.txt"); err == nil || err.Error() != "ftp: MDTM not supported by server" {
+		t.Errorf("ModTime: %v", err)
+	}
+	if err := f.SetModTime("a.txt", time.Now()); err == nil {
+		t.Errorf("SetModTime ohne MFMT")
+	}
+	if f.Supports("size") {
+		t.Errorf("Supports")
+	}
+
+	// RemoveDirAll ueber LIST
+	f.MakeDir("out")
+	f.MakeDir("out/sub")
+	s.put("out/sub/x.txt", []byte("x"))
+	if err := f.RemoveDirAll("out"); err != nil || s.file("out/sub/x.txt") != nil {
+		t.Errorf("RemoveDirAll mit LIST: %v", err)
+	}
+
+	// ohne SIZE: Upload von vorn
+	src := filepath.Join(t.TempDir(), "a.txt")
+	ioutil.WriteFile(src, []byte("neu"), 0666)
+	if err := f.ResumePutFile(src, "a.txt"); err != nil || string(s.file("a.txt")) != "neu" {
+		t.Errorf("ResumePutFile ohne SIZE: %v %q", err, s.file("a.txt"))
+	}
+}
+
+func Test_List(t *testing.T) {
+	now := time.Now().UTC()
+
+	// ohne Jahr: innerhalb der letzten 6 Monate, nie in der Zukunft
+	recent := now.AddDate(0, -1, 0)
+	hm := time.Date(recent.Year(), recent.Month(), recent.Day(), 10, 20, 0, 0, time.UTC)
+	unixRecent := fmt.Sprintf("%s %2d 10:20", hm.Format("Jan"), hm.Day())
+
+	type want struct {
+		name, typ string
+		size      int64
+		mtime     time.Time
+		link      string
+	}
+
+	for _, tc := range []struct {
+		name  string
+		lines []string
+		want  []want
+	}{
+		{"unix", []string{
+			"total 12",
+			"drwxr-xr-x   2 ftp      ftp          4096 Jan  1  2026 .",
+			"drwxr-xr-x   2 ftp      ftp          4096 Jan  1  2026 ..",
+			"drwxr-xr-x   2 ftp      ftp          4096 Mar  1  2025 out",
+			"-rw-r--r--   1 ftp      ftp        637616 Aug 15  2019 Differenz_20200_fileliste.txt",
+			"-rw-r--r--   1 ftp      ftp           123 " + unixRecent + " mit  zwei Leerzeichen.txt",
+			"lrwxrwxrwx   1 ftp      ftp            11 Aug 15  2019 aktuell -> out/2026.txt",
+			"-rw-r--r--   1 owner            1234 Aug 15  2019 ohne group.txt",
+			"crw-rw-rw-   1 root     root       1,   3 Aug 15  2019 null",
+		}, []want{
+			{"out", "D", 0, time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), ""},
+			{"Differenz_20200_fileliste.txt", "F", 637616, time.Date(2019, 8, 15, 0, 0, 0, 0, time.UTC), ""},
+			{"mit  zwei Leerzeichen.txt", "F", 123, hm, ""},
+			{"aktuell", "L", 11, time.Date(2019, 8, 15, 0, 0, 0, 0, time.UTC), "out/2026.txt"},
+			{"ohne group.txt", "F", 1234, time.Date(2019, 8, 15, 0, 0, 0, 0, time.UTC), ""},
+		}},
+		{"dos", []string{
+			"03-01-26  10:20AM       <DIR>          out",
+			"03-01-26  10:20PM                 1234 Differenz 20200.txt",
+			"12-31-2025  22:05                    7 iis24.txt",
+		}, []want{
+			{"out", "D", 0, time.Date(2026, 3, 1, 10, 20, 0, 0, time.UTC), ""},
+			{"Differenz 20200.txt", "F", 1234, time.Date(2026, 3, 1, 22, 20, 0, 0, time.UTC), ""},
+			{"iis24.txt", "F", 7, time.Date(2025, 12, 31, 22, 5, 0, 0, time.UTC), ""},
+		}},
+		{"eplf", []string{
+			"+i8388621.48594,m825718503,r,s280,\tdjb.html",
+			"+i8388621.50690,m824255907,/,\t514",
+			"+i1.2,m824255907,\tweder noch",
+		}, []want{
+			{"djb.html", "F", 280, time.Unix(825718503, 0).UTC(), ""},
+			{"514", "D", 0, time.Unix(824255907, 0).UTC(), ""},
+		}},
+	} {
+		s := newServer(t)
+		s.feat = []string{"EPSV", "SIZE"}
+		s.list = tc.lines
+		f := dial(t, s)
+
+		ff, err := f.ListFiles("")
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if len(*ff) != len(tc.want) {
+			t.Fatalf("%s: %d Eintraege, erwartet %d: %+v", tc.name, len(*ff), len(tc.want), *ff)
+		}
+
+		for i, w := range tc.want {
+			fi := (*ff)[i]
+			if fi.Name != w.name || fi.Type != w.typ || fi.Size != w.size || !fi.ModTime.Equal(w.mtime) || fi.Link != w.link {
+				t.Errorf("%s[%d]: %+v, erwartet %+v", tc.name, i, fi, w)
+			}
+		}
+	}
+}
+
+func Test_ListFallback(t *testing.T) {
+	// MLST bei FEAT, aber MLSD wird abgelehnt
+	s := newServer(t)
+	s.mlsd = false
+	f := dial(t, s)
+
+	s.put("a.txt", []byte("abc"))
+	for i := 0; i < 2; i++ {
+		ff, err := f.ListFiles("/")
+		if err != nil || len(*ff) != 1 || (*ff)[0].Name != "a.txt" || (*ff)[0].Size != 3 {
+			t.Errorf("ListFiles: %v %v", ff, err)
+		}
+	}
+}
+
+// testTLS #selbst signiertes Zertifikat fuer 127.0.0.1
+func testTLS(t *testing.T) (*tls.Config, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "ftp-test"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tpl, tpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	srv := &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	return srv, &tls.Config{RootCAs: pool}
+}
+
+func Test_TLS(t *testing.T) {
+	srv, cli := testTLS(t)
+
+	s := newServer(t)
+	s.tls = srv
+	s.reuse = true
+
+	f, err := ftp.ConnectTLS("user:geheim@"+s.addr(), 5, cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Quit()
+
+	if !f.IsTLS() {
+		t.Errorf("IsTLS")
+	}
+
+	data := testData(100000)
+	if err := f.Stor("a.bin", bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Stor("leer.bin", bytes.NewReader(nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(t.TempDir(), "a.bin")
+	if err := f.GetFile("a.bin", dst); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dst); !bytes.Equal(b, data) {
+		t.Errorf("GetFile: %d Bytes", len(b))
+	}
+
+	if ff, err := f.ListFiles(""); err != nil || len(*ff) != 1 {
+		t.Errorf("ListFiles: %v %v", ff, err)
+	}
+
+	s.mu.Lock()
+	n := s.resumed
+	s.mu.Unlock()
+	if n != 4 {
+		t.Errorf("Session-Reuse: %d von 4 Datenverbindungen", n)
+	}
+
+	// unbekannte CA
+	if _, err := ftp.DialFtpTLS(s.addr(), 5, nil); err == nil {
+		t.Errorf("DialFtpTLS ohne RootCAs: kein Fehler")
+	}
+
+	// Server ohne AUTH TLS
+	plain := newServer(t)
+	if _, err := ftp.DialFtpTLS(plain.addr(), 5, cli); err == nil || !strings.Contains(err.Error(), "504") {
+		t.Errorf("AUTH TLS: %v", err)
+	}
+}
+
+func Test_ImplicitTLS(t *testing.T) {
+	srv, cli := testTLS(t)
+
+	s := newServer(t)
+	s.tls = srv
+	s.implicit = true
+	s.reuse = true
+	s.put("a.txt", []byte("hallo"))
+
+	f, err := ftp.DialFtpImplicitTLS(s.addr(), 5, cli)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Quit()
+
+	if err := f.Login("user", "geheim"); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := f.Retr("a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := ioutil.ReadAll(r)
+	if err := r.Close(); err != nil || string(b) != "hallo" {
+		t.Errorf("Retr: %q %v", b, err)
+	}
+
+	// Reconnect nach Abbruch, auch mit TLS
+	s.put("big.bin", testData(300000))
+	s.mu.Lock()
+	s.drop = 50000
+	s.mu.Unlock()
+
+	dst := filepath.Join(t.TempDir(), "big.bin")
+	if err := f.GetFile("big.bin", dst); err != nil {
+		t.Fatal(err)
+	}
+	if b, _ := ioutil.ReadFile(dst); len(b) != 300000 {
+		t.Errorf("GetFile: %d Bytes", len(b))
+	}
+}
+
+func Test_Active(t *testing.T) {
+	srv, cli := testTLS(t)
+
+	// freier Port fuer den Bereich
+	ln, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+
+	for _, tc := range []struct {
+		name   string
+		mode   ftp.Mode
+		nopasv bool
+		noeprt bool
+		tls    bool
+		want   string
+	}{
+		{"aktiv", ftp.ModeActive, false, false, false, "EPRT"},
+		{"PORT", ftp.ModeActive, false, true, false, "PORT"},
+		{"auto", ftp.ModeAuto, true, false, false, "EPRT"},
+		{"tls", ftp.ModeActive, false, false, true, "EPRT"},
+		{"passiv", ftp.ModePassive, false, false, false, ""},
+	} {
+		s := newServer(t)
+		s.nopasv = tc.nopasv
+		s.noeprt = tc.noeprt
+
+		var (
+			f   *ftp.Ftp
+			err error
+		)
+		if tc.tls {
+			s.tls = srv
+			s.reuse = true
+			f, err = ftp.ConnectTLS("user:geheim@"+s.addr(), 5, cli)
+		} else {
+			f, err = ftp.Connect("user:geheim@"+s.addr(), 5)
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		defer f.Quit()
+
+		f.Mode = tc.mode
+		f.ActivePortMin, f.ActivePortMax = port, port
+
+		data := testData(100000)
+		if err := f.Stor("a.bin", bytes.NewReader(data)); err != nil {
+			t.Fatalf("%s: Stor: %v", tc.name, err)
+		}
+
+		dst := filepath.Join(t.TempDir(), "a.bin")
+		if err := f.GetFile("a.bin", dst); err != nil {
+			t.Fatalf("%s: GetFile: %v", tc.name, err)
+		}
+		if b, _ := ioutil.ReadFile(dst); !bytes.Equal(b, data) {
+			t.Errorf("%s: GetFile: %d Bytes", tc.name, len(b))
+		}
+
+		if ff, err := f.ListFiles(""); err != nil || len(*ff) != 1 {
+			t.Errorf("%s: ListFiles: %v %v", tc.name, ff, err)
+		}
+
+		s.mu.Lock()
+		active := s.active
+		s.mu.Unlock()
+
+		if tc.want == "" {
+			if len(active) != 0 {
+				t.Errorf("%s: aktiv trotz ModePassive: %v", tc.name, active)
+			}
+			continue
+		}
+
+		want := fmt.Sprintf("%s 127.0.0.1:%d", tc.want, port)
+		if len(active) != 3 || active[0] != want || active[2] != want {
+			t.Errorf("%s: %v, erwartet 3x %q", tc.name, active, want)
+		}
+	}
+
+	// ModePassive ohne EPSV/PASV: Fehler
+	s := newServer(t)
+	s.nopasv = true
+	f := dial(t, s)
+	f.Mode = ftp.ModePassive
+	if err := f.Stor("x", strings.NewReader("x")); err == nil {
+		t.Errorf("ModePassive ohne PASV: kein Fehler")
+	}
+
+	// angegebene Adresse fuer NAT
+	f.Mode = ftp.ModeActive
+	f.ActiveAddr = "127.0.0.1"
+	if err := f.Stor("x", strings.NewReader("x")); err != nil {
+		t.Errorf("ActiveAddr: %v", err)
+	}
+	f.ActiveAddr = "kein.ip"
+	if err := f.Stor("x", strings.NewReader("x")); err == nil {
+		t.Errorf("ActiveAddr ungueltig: kein Fehler")
+	}
+
+	// fremde Datenverbindung wird verworfen
+	s = newServer(t)
+	s.rogue = true
+	s.put("a.bin", testData(1000))
+	f = dial(t, s)
+	f.Mode = ftp.ModeActive
+	dst := filepath.Join(t.TempDir(), "a.bin")
+	if err := f.GetFile("a.bin", dst); err != nil {
+		t.Fatalf("rogue: %v", err)
+	}
+	if b, _ := ioutil.ReadFile(dst); !bytes.Equal(b, testData(1000)) {
+		t.Errorf("rogue: Daten vom fremden Rechner angenommen: %q", b)
+	}
+
+	// keine Datenverbindung nach 150: 425 gehoert nicht zum naechsten Befehl
+	s = newServer(t)
+	s.noconn = true
+	s.put("a.bin", testData(10))
+	f = dial(t, s)
+	f.Mode = ftp.ModeActive
+	f.Retries = 0
+	f.Timeout = 1
+	if err := f.GetFile("a.bin", dst); err == nil || !strings.Contains(err.Error(), "425") {
+		t.Errorf("noconn: %v", err)
+	}
+	if err := f.ChangeDir("/"); err != nil {
+		t.Errorf("noconn: Befehl danach: %v", err)
+	}
+
+	// ModeAuto: 4xx auf EPSV/PASV ist kein Grund fuer den aktiven Modus
+	s = newServer(t)
+	s.pasvErr = 421
+	f = dial(t, s)
+	f.Retries = 0
+	if err := f.Stor("x", strings.NewReader("x")); err == nil || !strings.Contains(err.Error(), "421") {
+		t.Errorf("ModeAuto 421: %v", err)
+	}
+	s.mu.Lock()
+	s.pasvErr = 0
+	s.mu.Unlock()
+	if err := f.Stor("x", strings.NewReader("x")); err != nil {
+		t.Errorf("ModeAuto nach 421: %v", err)
+	}
+	if len(s.active) != 0 {
+		t.Errorf("ModeAuto: aktiv nach 421: %v", s.active)
+	}
+}
@@ -0,0 +1,181 @@
+package ftp
+
+// ----------------------------------------------------------------------------------
+// manage.go (https://github.com/waldurbas/got)
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init: Delete, Rename, MakeDir, RemoveDir, ChangeDir, Size, ModTime
+//-----------------------------------------------------------------------------------
+//
+// DELE, RNFR/RNTO, MKD, RMD, CWD, PWD sind RFC 959 und immer verfuegbar,
+// SIZE, MDTM und MFMT nur wenn der Server sie bei FEAT meldet
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// UnsupportedError #Befehl wird laut FEAT vom Server nicht unterstuetzt
+type UnsupportedError struct {
+	Cmd string
+}
+
+func (e *UnsupportedError) Error() string {
+	return "ftp: " + e.Cmd + " not supported by server"
+}
+
+// Supports #cmd (z.B. "SIZE", "MFMT") bei FEAT gemeldet
+func (f *Ftp) Supports(cmd string) bool {
+	_, ok := f.mFeat[strings.ToUpper(cmd)]
+	return ok
+}
+
+func (f *Ftp) need(cmd string) error {
+	if !f.Supports(cmd) {
+		return &UnsupportedError{Cmd: cmd}
+	}
+
+	return nil
+}
+
+func errInvalidResponse(cmd string, m string) error {
+	return fmt.Errorf("invalid %s response: %q", cmd, m)
+}
+
+// Delete #Datei loeschen
+func (f *Ftp) Delete(path string) error {
+	_, _, err := f.cmd(250, "DELE %s", path)
+	return err
+}
+
+// Rename #from nach to umbenennen bzw. verschieben
+func (f *Ftp) Rename(from string, to string) error {
+	if _, _, err := f.cmd(350, "RNFR %s", from); err != nil {
+		return err
+	}
+
+	_, _, err := f.cmd(250, "RNTO %s", to)
+	return err
+}
+
+// MakeDir #
+func (f *Ftp) MakeDir(path string) error {
+	_, _, err := f.cmd(257, "MKD %s", path)
+	return err
+}
+
+// RemoveDir #leeres Verzeichnis loeschen
+func (f *Ftp) RemoveDir(path string) error {
+	_, _, err := f.cmd(250, "RMD %s", path)
+	return err
+}
+
+// RemoveDirAll #Verzeichnis mit Inhalt loeschen
+func (f *Ftp) RemoveDirAll(dir string) error {
+	ff, err := f.list(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, fi := range ff {
+		p := path.Join(dir, fi.Name)
+		if fi.IsDir() {
+			err = f.RemoveDirAll(p)
+		} else {
+			err = f.Delete(p)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return f.RemoveDir(dir)
+}
+
+// ChangeDir #
+func (f *Ftp) ChangeDir(path string) error {
+	_, _, err := f.cmd(250, "CWD %s", path)
+	return err
+}
+
+// CurrentDir #
+func (f *Ftp) CurrentDir() (string, error) {
+	_, m, err := f.cmd(257, "PWD")
+	if err != nil {
+		return "", err
+	}
+
+	return quotedPath(m)
+}
+
+// quotedPath #257 "/pfad mit ""quote""" is current directory
+func quotedPath(m string) (string, error) {
+	start := strings.Index(m, `"`)
+	if start == -1 {
+		return "", errInvalidResponse("PWD", m)
+	}
+
+	var sb strings.Builder
+	for i := start + 1; i < len(m); i++ {
+		if m[i] != '"' {
+			sb.WriteByte(m[i])
+			continue
+		}
+		if i+1 < len(m) && m[i+1] == '"' {
+			sb.WriteByte('"')
+			i++
+			continue
+		}
+		return sb.String(), nil
+	}
+
+	return "", errInvalidResponse("PWD", m)
+}
+
+// Size #Dateigroesse (SIZE)
+func (f *Ftp) Size(path string) (int64, error) {
+	if err := f.need("SIZE"); err != nil {
+		return 0, err
+	}
+
+	return f.size(path)
+}
+
+// ModTime #Aenderungszeit in UTC (MDTM)
+func (f *Ftp) ModTime(path string) (time.Time, error) {
+	if err := f.need("MDTM"); err != nil {
+		return time.Time{}, err
+	}
+
+	_, m, err := f.cmd(213, "MDTM %s", path)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// YYYYMMDDHHMMSS[.sss]
+	t, err := time.ParseInLocation("20060102150405", strings.SplitN(strings.TrimSpace(m), ".", 2)[0], time.UTC)
+	if err != nil {
+		return time.Time{}, errInvalidResponse("MDTM", m)
+	}
+
+	return t, nil
+}
+
+// SetModTime #Aenderungszeit setzen (MFMT)
+func (f *Ftp) SetModTime(path string, t time.Time) error {
+	if err := f.need("MFMT"); err != nil {
+		return err
+	}
+
+	_, _, err := f.cmd(213, "MFMT %s %s", t.UTC().Format("20060102150405"), path)
+	return err
+}
@@ -11,6 +11,7 @@ package ftp
 // ----------------------------------------------------------------------------------
 // HISTORY
 //-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Write, Timeout je Operation
 // 2020.10.03 (wu) Init
 //-----------------------------------------------------------------------------------
 
@@ -28,15 +29,29 @@ type FResponse struct {
 
 // Read #
 func (r *FResponse) Read(buf []byte) (int, error) {
+	r.deadline()
 	return r.con.Read(buf)
 }
 
+// Write #fuer Uploads
+func (r *FResponse) Write(buf []byte) (int, error) {
+	r.deadline()
+	return r.con.Write(buf)
+}
+
+func (r *FResponse) deadline() {
+	if r.ftp.Timeout > 0 {
+		r.con.SetDeadline(time.Now().Add(time.Duration(r.ftp.Timeout) * time.Second))
+	}
+}
+
 // Close #
 func (r *FResponse) Close() error {
 	if r.closed {
 		return nil
 	}
 	err := r.con.Close()
+	r.ftp.deadline()
 	_, _, err2 := r.ftp.con.ReadResponse(226)
 	if err2 != nil {
 		err = err2
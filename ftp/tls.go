@@ -0,0 +1,162 @@
+package ftp
+
+// ----------------------------------------------------------------------------------
+// tls.go (https://github.com/waldurbas/got)
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init: FTPS explizit (AUTH TLS) und implizit (990), PROT P
+//-----------------------------------------------------------------------------------
+//
+// Die Datenverbindungen verwenden die TLS-Session der Steuerverbindung weiter
+// (vsftpd: require_ssl_reuse, FileZilla Server), dafuer teilen sich beide
+// den ClientSessionCache und denselben ServerName.
+//
+//	cfg := &tls.Config{RootCAs: pool}
+//	f, err := ftp.ConnectTLS("user:pwd@ftp.partner.de:21", 30, cfg)
+
+import (
+	"crypto/tls"
+	"net"
+	"net/textproto"
+	"time"
+)
+
+// ImplicitPort #Standard-Port fuer implizites FTPS
+const ImplicitPort = "990"
+
+// DialFtpTLS #explizites FTPS: Klartext-Verbindung, danach AUTH TLS;
+// cfg darf nil sein (Systemzertifikate, ServerName aus addr)
+func DialFtpTLS(addr string, timeout int, cfg *tls.Config) (*Ftp, error) {
+	f := &Ftp{
+		Timeout: timeout,
+		Retries: DefaultRetries,
+		addr:    addr,
+		tlsCfg:  newTLSConfig(addr, cfg),
+	}
+
+	if err := f.dial(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// DialFtpImplicitTLS #implizites FTPS: TLS ab dem ersten Byte, ohne Port: 990
+func DialFtpImplicitTLS(addr string, timeout int, cfg *tls.Config) (*Ftp, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, ImplicitPort)
+	}
+
+	f := &Ftp{
+		Timeout:  timeout,
+		Retries:  DefaultRetries,
+		addr:     addr,
+		tlsCfg:   newTLSConfig(addr, cfg),
+		implicit: true,
+	}
+
+	if err := f.dial(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// ConnectTLS #wie Connect, mit explizitem FTPS
+func ConnectTLS(conStr string, timeout int, cfg *tls.Config) (*Ftp, error) {
+	return connect(conStr, func(addr string) (*Ftp, error) {
+		return DialFtpTLS(addr, timeout, cfg)
+	})
+}
+
+// IsTLS #Steuer- und Datenverbindungen verschluesselt
+func (f *Ftp) IsTLS() bool {
+	return f.tlsCfg != nil
+}
+
+// newTLSConfig #Kopie von cfg mit ServerName und ClientSessionCache
+func newTLSConfig(addr string, cfg *tls.Config) *tls.Config {
+	if cfg == nil {
+		cfg = &tls.Config{}
+	}
+
+	c := cfg.Clone()
+	if c.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		c.ServerName = host
+	}
+
+	if c.ClientSessionCache == nil {
+		c.ClientSessionCache = tls.NewLRUClientSessionCache(0)
+	}
+
+	return c
+}
+
+// handshake #TLS auf conn, mit Timeout
+func (f *Ftp) handshake(conn net.Conn) (*tls.Conn, error) {
+	tc := tls.Client(conn, f.tlsCfg)
+
+	if f.Timeout > 0 {
+		tc.SetDeadline(time.Now().Add(time.Duration(f.Timeout) * time.Second))
+	}
+
+	if err := tc.Handshake(); err != nil {
+		return nil, err
+	}
+
+	return tc, nil
+}
+
+// authTLS #AUTH TLS auf der Steuerverbindung
+func (f *Ftp) authTLS() error {
+	if _, _, err := f.cmd(234, "AUTH TLS"); err != nil {
+		return err
+	}
+
+	tc, err := f.handshake(f.ctl)
+	if err != nil {
+		return err
+	}
+
+	f.ctl = tc
+	f.con = textproto.NewConn(tc)
+	return nil
+}
+
+// protect #Datenverbindungen verschluesseln, nach dem Login
+func (f *Ftp) protect() error {
+	if _, _, err := f.cmd(200, "PBSZ 0"); err != nil {
+		return err
+	}
+
+	_, _, err := f.cmd(200, "PROT P")
+	return err
+}
+
+// dataHandshake #TLS auf der Datenverbindung nach 125/150,
+// bei Fehler die Antwort des Servers (z.B. 522 ohne Session-Reuse)
+func (f *Ftp) dataHandshake(conn net.Conn) (net.Conn, error) {
+	tc, err := f.handshake(conn)
+	if err == nil {
+		return tc, nil
+	}
+
+	conn.Close()
+	f.deadline()
+	if code, msg, err2 := f.con.ReadResponse(-1); err2 == nil && code >= 400 {
+		return nil, &textproto.Error{Code: code, Msg: msg}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,184 @@
+package ftp
+
+// ----------------------------------------------------------------------------------
+// transfer.go (https://github.com/waldurbas/got)
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Reconnect mit Verzeichnis, retryable nur Netzwerkfehler, Server groesser als lokal: Fehler
+// 2026.10.19 (wu) ohne SIZE beim Server von vorn
+// 2026.10.19 (wu) Init: PutFile, ResumeGetFile, ResumePutFile mit Reconnect
+//-----------------------------------------------------------------------------------
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// PutFile #srcFile als dstFile speichern, nach Verbindungsabbruch wird fortgesetzt
+func (f *Ftp) PutFile(srcFile string, dstFile string) error {
+	first := true
+
+	return f.retry(func() error {
+		if first {
+			first = false
+			return f.putFrom(srcFile, dstFile, 0)
+		}
+
+		return f.resumePut(srcFile, dstFile)
+	})
+}
+
+// ResumePutFile #Upload ab der Groesse von dstFile auf dem Server fortsetzen
+func (f *Ftp) ResumePutFile(srcFile string, dstFile string) error {
+	return f.retry(func() error {
+		return f.resumePut(srcFile, dstFile)
+	})
+}
+
+// ResumeGetFile #Download ab der Groesse von dstFile (lokal) fortsetzen
+func (f *Ftp) ResumeGetFile(srcFile string, dstFile string) error {
+	return f.retry(func() error {
+		return f.getFrom(srcFile, dstFile)
+	})
+}
+
+// retry #fn wiederholen, solange der Fehler voruebergehend ist (Netz, 4xx);
+// nach dem Reconnect wieder im aktuellen Verzeichnis
+func (f *Ftp) retry(fn func() error) error {
+	var dir string
+	if f.Retries > 0 {
+		dir, _ = f.CurrentDir()
+	}
+
+	err := fn()
+
+	for try := 0; err != nil && try < f.Retries && retryable(err); try++ {
+		if err = f.reconnect(dir); err == nil {
+			err = fn()
+		}
+	}
+
+	return err
+}
+
+// retryable #4xx, Verbindungsabbruch und Timeout; 5xx, TLS/Zertifikat
+// und ungueltige Antworten sind endgueltig
+func retryable(err error) bool {
+	var te *textproto.Error
+	if errors.As(err, &te) {
+		return te.Code/100 == 4
+	}
+
+	// TLS-Alert des Servers
+	var oe *net.OpError
+	if errors.As(err, &oe) && oe.Op == "remote error" {
+		return false
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE)
+}
+
+func (f *Ftp) getFrom(srcFile string, dstFile string) error {
+	fp, err := os.OpenFile(dstFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	st, err := fp.Stat()
+	if err != nil {
+		return err
+	}
+
+	r, err := f.RetrFrom(srcFile, uint64(st.Size()))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(fp, r)
+	if err2 := r.Close(); err == nil {
+		err = err2
+	}
+
+	return err
+}
+
+func (f *Ftp) putFrom(srcFile string, dstFile string, offset int64) error {
+	fp, err := os.Open(srcFile)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+
+	if offset == 0 {
+		return f.Stor(dstFile, fp)
+	}
+
+	if _, err := fp.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	// REST STREAM: REST + STOR, sonst APPE
+	if _, ok := f.mFeat["REST"]; ok {
+		return f.StorFrom(dstFile, fp, uint64(offset))
+	}
+
+	return f.Append(dstFile, fp)
+}
+
+func (f *Ftp) resumePut(srcFile string, dstFile string) error {
+	if !f.Supports("SIZE") {
+		return f.putFrom(srcFile, dstFile, 0)
+	}
+
+	n, err := f.size(dstFile)
+	if err != nil {
+		// Datei noch nicht vorhanden
+		if e, ok := err.(*textproto.Error); ok && e.Code == 550 {
+			n = 0
+		} else {
+			return err
+		}
+	}
+
+	st, err := os.Stat(srcFile)
+	if err != nil {
+		return err
+	}
+
+	if n > st.Size() {
+		return fmt.Errorf("resume %s: %d bytes on server, %s has only %d", dstFile, n, srcFile, st.Size())
+	}
+
+	return f.putFrom(srcFile, dstFile, n)
+}
+
+// size #SIZE im Binaer-Modus
+func (f *Ftp) size(path string) (int64, error) {
+	_, m, err := f.cmd(213, "SIZE %s", path)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(m), 10, 64)
+}
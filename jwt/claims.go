@@ -0,0 +1,282 @@
+package jwt
+
+// ----------------------------------------------------------------------------------
+// claims.go (https://github.com/waldurbas/got): validation of registered claims
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) iat nur mit MaxAge pruefen
+// 2026.10.19 (wu) RegisteredClaims, DecodeClaims, SetClaims, TMap.AsString...
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTokenNotYetValid #nbf liegt in der Zukunft
+	ErrTokenNotYetValid = errors.New("Token is not valid yet")
+	// ErrTokenIssuedAt #iat liegt in der Zukunft oder ist aelter als MaxAge
+	ErrTokenIssuedAt = errors.New("Token has invalid issued at")
+	// ErrTokenAudience #
+	ErrTokenAudience = errors.New("Token has wrong audience")
+	// ErrTokenIssuer #
+	ErrTokenIssuer = errors.New("Token has wrong issuer")
+	// ErrTokenSubject #
+	ErrTokenSubject = errors.New("Token has wrong subject")
+	// ErrTokenClaimMissing #exp fehlt bei RequireExp
+	ErrTokenClaimMissing = errors.New("Token has missing claim")
+)
+
+// Validation #Pruefung der registrierten Claims
+// exp und nbf werden geprueft, wenn vorhanden, iat nur mit MaxAge; iss, aud und sub nur, wenn gesetzt
+type Validation struct {
+	Leeway     time.Duration    // erlaubte Uhrenabweichung
+	Now        func() time.Time // Uhr, default time.Now
+	Issuer     string
+	Audience   string // muss in aud (string oder Array) enthalten sein
+	Subject    string
+	RequireExp bool
+	MaxAge     time.Duration // max. Alter seit iat, 0: ohne
+}
+
+// SetExpiry #exp = iat + d
+func (t *JWToken) SetExpiry(d time.Duration) {
+	iat := t.Claims.AsInt64("iat")
+	if iat == 0 {
+		iat = time.Now().UTC().Unix()
+		t.Claims["iat"] = iat
+	}
+
+	t.Claims["exp"] = iat + int64(d/time.Second)
+}
+
+// ValidateClaims #o == nil: nur exp und nbf
+func (t *JWToken) ValidateClaims(o *Validation) error {
+	if o == nil {
+		o = &Validation{}
+	}
+
+	now := time.Now()
+	if o.Now != nil {
+		now = o.Now()
+	}
+
+	leeway := int64(o.Leeway / time.Second)
+	unix := now.Unix()
+
+	if _, ok := t.Claims["exp"]; ok {
+		if unix-leeway >= t.Claims.AsInt64("exp") {
+			return ErrTokenExpired
+		}
+	} else if o.RequireExp {
+		return ErrTokenClaimMissing
+	}
+
+	if _, ok := t.Claims["nbf"]; ok {
+		if unix+leeway < t.Claims.AsInt64("nbf") {
+			return ErrTokenNotYetValid
+		}
+	}
+
+	// iat nur mit MaxAge, sonst scheitern frische Token an der Uhrenabweichung
+	if _, ok := t.Claims["iat"]; ok && o.MaxAge > 0 {
+		iat := t.Claims.AsInt64("iat")
+		if unix+leeway < iat {
+			return ErrTokenIssuedAt
+		}
+
+		if unix-leeway > iat+int64(o.MaxAge/time.Second) {
+			return ErrTokenExpired
+		}
+	}
+
+	if o.Issuer != "" && t.Claims["iss"] != o.Issuer {
+		return ErrTokenIssuer
+	}
+
+	if o.Subject != "" && t.Claims["sub"] != o.Subject {
+		return ErrTokenSubject
+	}
+
+	if o.Audience != "" && !t.hasAudience(o.Audience) {
+		return ErrTokenAudience
+	}
+
+	return nil
+}
+
+// hasAudience #aud als string oder Array
+func (t *JWToken) hasAudience(aud string) bool {
+	switch a := t.Claims["aud"].(type) {
+	case string:
+		return a == aud
+	case []string:
+		for _, s := range a {
+			if s == aud {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, s := range a {
+			if s == aud {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// RegisteredClaims #zum Einbetten in eigene Claims-Structs
+//
+//	type MyClaims struct {
+//		jwt.RegisteredClaims
+//		App  string `json:"app"`
+//		Perm string `json:"perm"`
+//	}
+type RegisteredClaims struct {
+	Issuer    string   `json:"iss,omitempty"`
+	Subject   string   `json:"sub,omitempty"`
+	Audience  Audience `json:"aud,omitempty"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+	NotBefore int64    `json:"nbf,omitempty"`
+	IssuedAt  int64    `json:"iat,omitempty"`
+	ID        string   `json:"jti,omitempty"`
+}
+
+// Audience #aud als string oder Array
+type Audience []string
+
+// UnmarshalJSON #
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = Audience{s}
+		return nil
+	}
+
+	var ss []string
+	if err := json.Unmarshal(b, &ss); err != nil {
+		return err
+	}
+
+	*a = ss
+	return nil
+}
+
+// MarshalJSON #ein Eintrag als string
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+
+	return json.Marshal([]string(a))
+}
+
+// DecodeClaims #Payload in ein Struct (z.B. mit eingebetteten RegisteredClaims)
+func (t *JWToken) DecodeClaims(v interface{}) error {
+	var b []byte
+
+	ss := strings.Split(t.RawData, ".")
+	if len(ss) == 3 {
+		b, _ = b64.DecodeString(ss[1])
+	}
+
+	if b == nil {
+		var err error
+		if b, err = json.Marshal(t.Claims); err != nil {
+			return err
+		}
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// SetClaims #Claims aus einem Struct, iat bleibt erhalten wenn v keins hat
+func (t *JWToken) SetClaims(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	m := TMap{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if err := d.Decode(&m); err != nil {
+		return err
+	}
+
+	if _, ok := m["iat"]; !ok {
+		if iat, ok := t.Claims["iat"]; ok {
+			m["iat"] = iat
+		}
+	}
+
+	t.Claims = m
+	return nil
+}
+
+// AsString #"" wenn nicht vorhanden oder kein string
+func (d *TMap) AsString(key string) string {
+	switch v := (*d)[key].(type) {
+	case string:
+		return v
+	}
+
+	return ""
+}
+
+// AsStrings #string oder Array von strings
+func (d *TMap) AsStrings(key string) []string {
+	switch v := (*d)[key].(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		ss := make([]string, 0, len(v))
+		for _, x := range v {
+			if s, ok := x.(string); ok {
+				ss = append(ss, s)
+			}
+		}
+		return ss
+	}
+
+	return nil
+}
+
+// AsTime #NumericDate (Sekunden seit 1970), zero wenn nicht vorhanden
+func (d *TMap) AsTime(key string) time.Time {
+	if _, ok := (*d)[key]; !ok {
+		return time.Time{}
+	}
+
+	return time.Unix(d.AsInt64(key), 0)
+}
+
+// AsBool #bool, "true"/"1" oder Zahl != 0
+func (d *TMap) AsBool(key string) bool {
+	switch v := (*d)[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	}
+
+	return d.AsInt64(key) != 0
+}
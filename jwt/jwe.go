@@ -0,0 +1,326 @@
+package jwt
+
+// ----------------------------------------------------------------------------------
+// jwe.go (https://github.com/waldurbas/got): compact JSON Web Encryption (RFC 7516)
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) A256KW: CEK muss 32 Bytes haben
+// 2026.10.19 (wu) Init: alg dir und A256KW, enc A256GCM, nested JWT
+//-----------------------------------------------------------------------------------
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrDecrypt #Token kann nicht entschluesselt werden
+	ErrDecrypt = errors.New("Token cannot be decrypted")
+)
+
+// jweAlgs #Key-Management, Content-Encryption ist immer A256GCM
+var jweAlgs = map[string]bool{"dir": true, "A256KW": true}
+
+// Decrypter #*JWK oder *KeySet
+type Decrypter interface {
+	Decrypt(s string) ([]byte, TMap, error)
+}
+
+// Encrypt #kompakte JWE mit k (oct, 32 Bytes), alg: "dir" oder "A256KW" ("": k.Alg)
+// hdr: zusaetzliche Header (z.B. cty), darf nil sein
+func Encrypt(plain []byte, alg string, k *JWK, hdr TMap) (string, error) {
+	if alg == "" {
+		alg = k.Alg
+	}
+
+	if !jweAlgs[alg] {
+		return "", ErrAlgUnsupported
+	}
+
+	kek, ok := k.key.([]byte)
+	if !ok || len(kek) != 32 {
+		return "", ErrKeyType
+	}
+
+	h := TMap{}
+	for kk, v := range hdr {
+		h[kk] = v
+	}
+	h["alg"] = alg
+	h["enc"] = "A256GCM"
+	if k.Kid != "" {
+		h["kid"] = k.Kid
+	}
+
+	cek := kek
+	var ekey []byte
+	if alg == "A256KW" {
+		cek = make([]byte, 32)
+		if _, err := rand.Read(cek); err != nil {
+			return "", err
+		}
+
+		var err error
+		if ekey, err = aesKeyWrap(kek, cek); err != nil {
+			return "", err
+		}
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	shdr := h.AsBase64()
+	sealed := gcm.Seal(nil, iv, plain, []byte(shdr))
+	n := len(sealed) - gcm.Overhead()
+
+	return shdr + "." + b64.EncodeToString(ekey) + "." + b64.EncodeToString(iv) + "." +
+		b64.EncodeToString(sealed[:n]) + "." + b64.EncodeToString(sealed[n:]), nil
+}
+
+// Decrypt #Decrypter
+func (k *JWK) Decrypt(s string) ([]byte, TMap, error) {
+	ss, h, err := splitJWE(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	alg, _ := h["alg"].(string)
+	if k.Alg != "" && k.Alg != alg {
+		return nil, nil, ErrAlgMismatch
+	}
+
+	kek, ok := k.key.([]byte)
+	if !ok || len(kek) != 32 {
+		return nil, nil, ErrKeyType
+	}
+
+	ekey, err1 := b64.DecodeString(ss[1])
+	iv, err2 := b64.DecodeString(ss[2])
+	ct, err3 := b64.DecodeString(ss[3])
+	tag, err4 := b64.DecodeString(ss[4])
+	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+		return nil, nil, ErrTokenNotValid
+	}
+
+	cek := kek
+	switch alg {
+	case "dir":
+		if len(ekey) != 0 {
+			return nil, nil, ErrTokenNotValid
+		}
+	case "A256KW":
+		// enc ist immer A256GCM: CEK muss 32 Bytes haben
+		if cek, err = aesKeyUnwrap(kek, ekey); err != nil || len(cek) != 32 {
+			return nil, nil, ErrDecrypt
+		}
+	}
+
+	gcm, err := newGCM(cek)
+	if err != nil {
+		return nil, nil, ErrDecrypt
+	}
+
+	if len(iv) != gcm.NonceSize() || len(tag) != gcm.Overhead() {
+		return nil, nil, ErrTokenNotValid
+	}
+
+	plain, err := gcm.Open(nil, iv, append(ct, tag...), []byte(ss[0]))
+	if err != nil {
+		return nil, nil, ErrDecrypt
+	}
+
+	return plain, h, nil
+}
+
+// Decrypt #Schluessel ueber kid, ohne kid werden alle oct-Schluessel probiert
+func (ks *KeySet) Decrypt(s string) ([]byte, TMap, error) {
+	_, h, err := splitJWE(s)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if kid, _ := h["kid"].(string); kid != "" {
+		k := ks.Lookup(kid)
+		if k == nil {
+			return nil, nil, ErrKeyUnknown
+		}
+		return k.Decrypt(s)
+	}
+
+	err = ErrKeyUnknown
+	for _, k := range ks.Keys() {
+		if k.Kty != "oct" {
+			continue
+		}
+
+		var b []byte
+		if b, h, err = k.Decrypt(s); err == nil {
+			return b, h, nil
+		}
+	}
+
+	return nil, nil, err
+}
+
+// SignEncrypt #nested JWT: erst signieren, dann verschluesseln (cty "JWT")
+func (t *JWToken) SignEncrypt(sg Signer, alg string, k *JWK) (string, error) {
+	s, err := t.Sign(sg)
+	if err != nil {
+		return "", err
+	}
+
+	return Encrypt([]byte(s), alg, k, TMap{"cty": "JWT"})
+}
+
+// ParseEncrypted #entschluesselt und parst das innere JWT, danach Verify aufrufen
+func (t *JWToken) ParseEncrypted(s string, d Decrypter) error {
+	b, h, err := d.Decrypt(s)
+	if err != nil {
+		return err
+	}
+
+	if cty, _ := h["cty"].(string); !strings.EqualFold(cty, "JWT") {
+		return ErrTokenNotValid
+	}
+
+	return t.Parse(string(b))
+}
+
+// IsEncrypted #kompakte JWE hat 5 Teile
+func IsEncrypted(s string) bool {
+	return strings.Count(s, ".") == 4
+}
+
+func splitJWE(s string) ([]string, TMap, error) {
+	ss := strings.Split(s, ".")
+	if len(ss) != 5 {
+		return nil, nil, ErrTokenNotValid
+	}
+
+	b, err := b64.DecodeString(ss[0])
+	if err != nil {
+		return nil, nil, ErrTokenNotValid
+	}
+
+	h := TMap{}
+	if err := json.Unmarshal(b, &h); err != nil {
+		return nil, nil, ErrTokenNotValid
+	}
+
+	alg, _ := h["alg"].(string)
+	if !jweAlgs[alg] {
+		return nil, nil, ErrAlgUnsupported
+	}
+
+	if h["enc"] != "A256GCM" {
+		return nil, nil, ErrAlgUnsupported
+	}
+
+	// zip und crit werden nicht unterstuetzt
+	if _, ok := h["zip"]; ok {
+		return nil, nil, ErrAlgUnsupported
+	}
+	if _, ok := h["crit"]; ok {
+		return nil, nil, ErrAlgUnsupported
+	}
+
+	return ss, h, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	blk, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(blk)
+}
+
+var kwIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// aesKeyWrap #RFC 3394
+func aesKeyWrap(kek []byte, cek []byte) ([]byte, error) {
+	if len(cek)%8 != 0 || len(cek) < 16 {
+		return nil, ErrKeyType
+	}
+
+	blk, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(cek) / 8
+	r := make([]byte, 8+len(cek))
+	copy(r, kwIV)
+	copy(r[8:], cek)
+
+	b := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(b, r[:8])
+			copy(b[8:], r[i*8:i*8+8])
+			blk.Encrypt(b, b)
+
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(r[:8], binary.BigEndian.Uint64(b[:8])^t)
+			copy(r[i*8:], b[8:])
+		}
+	}
+
+	return r, nil
+}
+
+// aesKeyUnwrap #RFC 3394
+func aesKeyUnwrap(kek []byte, wrapped []byte) ([]byte, error) {
+	if len(wrapped)%8 != 0 || len(wrapped) < 24 {
+		return nil, ErrDecrypt
+	}
+
+	blk, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+	r := make([]byte, len(wrapped))
+	copy(r, wrapped)
+
+	b := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:8], binary.BigEndian.Uint64(r[:8])^t)
+			copy(b[8:], r[i*8:i*8+8])
+			blk.Decrypt(b, b)
+
+			copy(r[:8], b[:8])
+			copy(r[i*8:], b[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(r[:8], kwIV) != 1 {
+		return nil, ErrDecrypt
+	}
+
+	return r[8:], nil
+}
@@ -0,0 +1,533 @@
+package jwt
+
+// ----------------------------------------------------------------------------------
+// jwk.go (https://github.com/waldurbas/got): JSON Web Key (RFC 7517) and key sets
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) privater EC/OKP-Schluessel muss zu X/Y passen
+// 2026.10.19 (wu) Init: oct, RSA, EC, OKP; KeySet mit kid, JWKS-Handler
+//-----------------------------------------------------------------------------------
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/waldurbas/got/htx"
+)
+
+var (
+	// ErrKeyUnknown #kid nicht im KeySet
+	ErrKeyUnknown = errors.New("Key id unknown")
+	// ErrKeyInvalid #JWK unvollstaendig oder fehlerhaft
+	ErrKeyInvalid = errors.New("Key is invalid")
+)
+
+// JWK #JSON Web Key
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	K   string `json:"k,omitempty"`   // oct
+	N   string `json:"n,omitempty"`   // RSA
+	E   string `json:"e,omitempty"`   // RSA
+	Crv string `json:"crv,omitempty"` // EC, OKP
+	X   string `json:"x,omitempty"`   // EC, OKP
+	Y   string `json:"y,omitempty"`   // EC
+
+	D  string `json:"d,omitempty"` // private: RSA, EC, OKP
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	Dp string `json:"dp,omitempty"`
+	Dq string `json:"dq,omitempty"`
+	Qi string `json:"qi,omitempty"`
+
+	key interface{}
+}
+
+var b64 = base64.RawURLEncoding
+
+// NewJWK #key: []byte (oct), *rsa.*, *ecdsa.*, ed25519.*
+func NewJWK(key interface{}, kid string, alg string) (*JWK, error) {
+	k := &JWK{Kid: kid, Alg: alg, key: key}
+
+	switch x := key.(type) {
+	case []byte:
+		k.Kty = "oct"
+		k.K = b64.EncodeToString(x)
+
+	case *rsa.PublicKey:
+		k.setRSA(x)
+	case *rsa.PrivateKey:
+		k.setRSA(&x.PublicKey)
+		x.Precompute()
+		k.D = b64.EncodeToString(x.D.Bytes())
+		if len(x.Primes) == 2 {
+			k.P = b64.EncodeToString(x.Primes[0].Bytes())
+			k.Q = b64.EncodeToString(x.Primes[1].Bytes())
+			k.Dp = b64.EncodeToString(x.Precomputed.Dp.Bytes())
+			k.Dq = b64.EncodeToString(x.Precomputed.Dq.Bytes())
+			k.Qi = b64.EncodeToString(x.Precomputed.Qinv.Bytes())
+		}
+
+	case *ecdsa.PublicKey:
+		if err := k.setEC(x); err != nil {
+			return nil, err
+		}
+	case *ecdsa.PrivateKey:
+		if err := k.setEC(&x.PublicKey); err != nil {
+			return nil, err
+		}
+		k.D = b64.EncodeToString(x.D.FillBytes(make([]byte, ecSize(x.Curve))))
+
+	case ed25519.PublicKey:
+		k.Kty, k.Crv = "OKP", "Ed25519"
+		k.X = b64.EncodeToString(x)
+	case ed25519.PrivateKey:
+		k.Kty, k.Crv = "OKP", "Ed25519"
+		k.X = b64.EncodeToString(x.Public().(ed25519.PublicKey))
+		k.D = b64.EncodeToString(x.Seed())
+
+	default:
+		return nil, ErrKeyType
+	}
+
+	if alg != "" && !jweAlgs[alg] {
+		if _, err := NewVerifier(alg, key); err != nil {
+			return nil, err
+		}
+	}
+
+	return k, nil
+}
+
+func (k *JWK) setRSA(pub *rsa.PublicKey) {
+	k.Kty = "RSA"
+	k.N = b64.EncodeToString(pub.N.Bytes())
+	k.E = b64.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+}
+
+func (k *JWK) setEC(pub *ecdsa.PublicKey) error {
+	switch pub.Curve {
+	case elliptic.P256():
+		k.Crv = "P-256"
+	case elliptic.P384():
+		k.Crv = "P-384"
+	case elliptic.P521():
+		k.Crv = "P-521"
+	default:
+		return ErrKeyType
+	}
+
+	n := ecSize(pub.Curve)
+	k.Kty = "EC"
+	k.X = b64.EncodeToString(pub.X.FillBytes(make([]byte, n)))
+	k.Y = b64.EncodeToString(pub.Y.FillBytes(make([]byte, n)))
+	return nil
+}
+
+func ecSize(c elliptic.Curve) int {
+	return (c.Params().BitSize + 7) / 8
+}
+
+// ParseJWK #
+func ParseJWK(b []byte) (*JWK, error) {
+	k := &JWK{}
+	if err := json.Unmarshal(b, k); err != nil {
+		return nil, err
+	}
+
+	if err := k.decode(); err != nil {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// decode #JSON-Felder -> crypto key
+func (k *JWK) decode() error {
+	num := func(s string) (*big.Int, error) {
+		b, err := b64.DecodeString(s)
+		if err != nil || len(b) == 0 {
+			return nil, ErrKeyInvalid
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	var err error
+	switch k.Kty {
+	case "oct":
+		var b []byte
+		if b, err = b64.DecodeString(k.K); err != nil || len(b) == 0 {
+			return ErrKeyInvalid
+		}
+		k.key = b
+
+	case "RSA":
+		pub := &rsa.PublicKey{}
+		var e *big.Int
+		if pub.N, err = num(k.N); err != nil {
+			return err
+		}
+		if e, err = num(k.E); err != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return ErrKeyInvalid
+		}
+		pub.E = int(e.Int64())
+		k.key = pub
+
+		if k.D != "" {
+			priv := &rsa.PrivateKey{PublicKey: *pub, Primes: make([]*big.Int, 2)}
+			if priv.D, err = num(k.D); err != nil {
+				return err
+			}
+			if priv.Primes[0], err = num(k.P); err != nil {
+				return err
+			}
+			if priv.Primes[1], err = num(k.Q); err != nil {
+				return err
+			}
+			if priv.Validate() != nil {
+				return ErrKeyInvalid
+			}
+			priv.Precompute()
+			k.key = priv
+		}
+
+	case "EC":
+		var c elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			c = elliptic.P256()
+		case "P-384":
+			c = elliptic.P384()
+		case "P-521":
+			c = elliptic.P521()
+		default:
+			return ErrKeyType
+		}
+
+		pub := &ecdsa.PublicKey{Curve: c}
+		if pub.X, err = num(k.X); err != nil {
+			return err
+		}
+		if pub.Y, err = num(k.Y); err != nil {
+			return err
+		}
+		if !c.IsOnCurve(pub.X, pub.Y) {
+			return ErrKeyInvalid
+		}
+		k.key = pub
+
+		if k.D != "" {
+			priv := &ecdsa.PrivateKey{PublicKey: *pub}
+			if priv.D, err = num(k.D); err != nil {
+				return err
+			}
+
+			// D muss zu X/Y passen, sonst nicht pruefbare Signaturen
+			if priv.D.Sign() <= 0 || priv.D.Cmp(c.Params().N) >= 0 {
+				return ErrKeyInvalid
+			}
+			if x, y := c.ScalarBaseMult(priv.D.FillBytes(make([]byte, ecSize(c)))); x.Cmp(pub.X) != 0 || y.Cmp(pub.Y) != 0 {
+				return ErrKeyInvalid
+			}
+			k.key = priv
+		}
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return ErrKeyType
+		}
+
+		var x []byte
+		if x, err = b64.DecodeString(k.X); err != nil || len(x) != ed25519.PublicKeySize {
+			return ErrKeyInvalid
+		}
+		k.key = ed25519.PublicKey(x)
+
+		if k.D != "" {
+			d, err := b64.DecodeString(k.D)
+			if err != nil || len(d) != ed25519.SeedSize {
+				return ErrKeyInvalid
+			}
+			priv := ed25519.NewKeyFromSeed(d)
+			if !bytes.Equal(priv.Public().(ed25519.PublicKey), x) {
+				return ErrKeyInvalid
+			}
+			k.key = priv
+		}
+
+	default:
+		return ErrKeyType
+	}
+
+	return nil
+}
+
+// Key #crypto key ([]byte, *rsa.PrivateKey, *ecdsa.PublicKey, ...)
+func (k *JWK) Key() interface{} {
+	return k.key
+}
+
+// IsPrivate #oct oder mit privatem Anteil
+func (k *JWK) IsPrivate() bool {
+	return k.Kty == "oct" || k.D != ""
+}
+
+// Public #ohne privaten Anteil, nil bei oct
+func (k *JWK) Public() *JWK {
+	if k.Kty == "oct" {
+		return nil
+	}
+
+	p := &JWK{Kty: k.Kty, Kid: k.Kid, Use: k.Use, Alg: k.Alg, N: k.N, E: k.E, Crv: k.Crv, X: k.X, Y: k.Y}
+	switch x := k.key.(type) {
+	case *rsa.PrivateKey:
+		p.key = &x.PublicKey
+	case *ecdsa.PrivateKey:
+		p.key = &x.PublicKey
+	case ed25519.PrivateKey:
+		p.key = x.Public()
+	default:
+		p.key = x
+	}
+
+	return p
+}
+
+// algFor #k.Alg oder Default je Schluesseltyp
+func (k *JWK) algFor(alg string) string {
+	if k.Alg != "" {
+		return k.Alg
+	}
+
+	if alg != "" {
+		return alg
+	}
+
+	switch k.Kty {
+	case "oct":
+		return "HS256"
+	case "RSA":
+		return "RS256"
+	case "EC":
+		if k.Crv == "P-384" {
+			return "ES384"
+		}
+		return "ES256"
+	case "OKP":
+		return "EdDSA"
+	}
+
+	return ""
+}
+
+// Signer #mit kid im Header
+func (k *JWK) Signer() (Signer, error) {
+	sg, err := NewSigner(k.algFor(""), k.key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &kidSigner{sg, k.Kid}, nil
+}
+
+// Verifier #alg aus dem Token-Header, muss zu k.Alg und zum Schluesseltyp passen
+func (k *JWK) Verifier(alg string) (Verifier, error) {
+	if k.Alg != "" && alg != "" && k.Alg != alg {
+		return nil, ErrAlgMismatch
+	}
+
+	if jweAlgs[k.Alg] {
+		return nil, ErrKeyType
+	}
+
+	return NewVerifier(k.algFor(alg), k.key)
+}
+
+type kidSigner struct {
+	Signer
+	kid string
+}
+
+// KeyID #
+func (s *kidSigner) KeyID() string {
+	return s.kid
+}
+
+//------------- KeySet ------------------------
+
+// KeySet #mehrere aktive Schluessel (Rotation), Auswahl ueber kid
+type KeySet struct {
+	mu   sync.RWMutex
+	keys []*JWK
+}
+
+type jwks struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// NewKeySet #
+func NewKeySet(keys ...*JWK) *KeySet {
+	return &KeySet{keys: keys}
+}
+
+// ParseJWKS #{"keys":[...]}
+func ParseJWKS(b []byte) (*KeySet, error) {
+	ks := &KeySet{}
+	if err := json.Unmarshal(b, ks); err != nil {
+		return nil, err
+	}
+
+	return ks, nil
+}
+
+// UnmarshalJSON #
+func (ks *KeySet) UnmarshalJSON(b []byte) error {
+	var x jwks
+	if err := json.Unmarshal(b, &x); err != nil {
+		return err
+	}
+
+	for _, k := range x.Keys {
+		if err := k.decode(); err != nil {
+			return err
+		}
+	}
+
+	ks.mu.Lock()
+	ks.keys = x.Keys
+	ks.mu.Unlock()
+
+	return nil
+}
+
+// MarshalJSON #
+func (ks *KeySet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jwks{Keys: ks.Keys()})
+}
+
+// Keys #
+func (ks *KeySet) Keys() []*JWK {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return append([]*JWK{}, ks.keys...)
+}
+
+// Add #vorhandener Schluessel mit gleicher kid wird ersetzt
+func (ks *KeySet) Add(k *JWK) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	for i, x := range ks.keys {
+		if k.Kid != "" && x.Kid == k.Kid {
+			ks.keys[i] = k
+			return
+		}
+	}
+
+	ks.keys = append(ks.keys, k)
+}
+
+// Remove #
+func (ks *KeySet) Remove(kid string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	for i, x := range ks.keys {
+		if x.Kid == kid {
+			ks.keys = append(ks.keys[:i], ks.keys[i+1:]...)
+			return
+		}
+	}
+}
+
+// Lookup #
+func (ks *KeySet) Lookup(kid string) *JWK {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, x := range ks.keys {
+		if x.Kid == kid {
+			return x
+		}
+	}
+
+	return nil
+}
+
+// Public #nur oeffentliche Schluessel, oct wird nicht veroeffentlicht
+func (ks *KeySet) Public() *KeySet {
+	p := &KeySet{}
+	for _, k := range ks.Keys() {
+		if pk := k.Public(); pk != nil {
+			p.keys = append(p.keys, pk)
+		}
+	}
+
+	return p
+}
+
+// Verify #Schluessel ueber kid, ohne kid werden alle passenden Schluessel probiert
+func (ks *KeySet) Verify(t *JWToken, o ...*Validation) error {
+	alg, _ := t.Head["alg"].(string)
+
+	if kid, _ := t.Head["kid"].(string); kid != "" {
+		k := ks.Lookup(kid)
+		if k == nil {
+			return ErrKeyUnknown
+		}
+
+		v, err := k.Verifier(alg)
+		if err != nil {
+			return err
+		}
+
+		return t.Verify(v, o...)
+	}
+
+	err := ErrKeyUnknown
+	for _, k := range ks.Keys() {
+		v, e := k.Verifier(alg)
+		if e != nil {
+			continue
+		}
+
+		if err = t.verifySign(v); err == nil {
+			return t.Verify(v, o...)
+		}
+	}
+
+	return err
+}
+
+// Handler #veroeffentlicht die oeffentlichen Schluessel als JWKS
+func (ks *KeySet) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			htx.WriteResponseMsg("jwks", w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		htx.WriteResponse(w, http.StatusOK, ks.Public())
+	})
+}
@@ -11,12 +11,16 @@ package jwt
 // ----------------------------------------------------------------------------------
 // HISTORY
 //-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) typ: AcceptTyp statt nur "JWT"
+// 2026.10.19 (wu) kid im Header
+// 2026.10.19 (wu) Valid/Verify mit ValidateClaims
+// 2026.10.19 (wu) Sign, Verify mit Signer/Verifier
+// 2026.10.19 (wu) RFC 7519: base64url, Signatur als base64url, LegacyHexSign
 // 2020.09.06 (wu) Init
 //-----------------------------------------------------------------------------------
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
+	"crypto"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -30,12 +34,20 @@ var (
 	ErrTokenExpired = errors.New("Token is expired")
 	// ErrTokenNotValid #
 	ErrTokenNotValid = errors.New("Token is not valid")
+
+	// LegacyHexSign #akzeptiert in Valid auch alte Tokens mit hex-Signatur
+	// und base64 (std) Header/Payload, nur fuer die Umstellungszeit
+	LegacyHexSign = false
+
+	// AcceptTyp #erlaubte typ-Werte im Header (ohne Gross-/Kleinschreibung), ohne typ immer erlaubt;
+	// at+jwt: RFC 9068 Access-Token
+	AcceptTyp = []string{"JWT", "at+jwt", "application/jwt", "application/at+jwt"}
 )
 
 // TMap #
 type TMap map[string]interface{}
 
-// JWToken with HS256
+// JWToken #Default HS256, weitere Algorithmen ueber Sign/Verify
 type JWToken struct {
 	RawData string
 	Head    TMap
@@ -55,6 +67,8 @@ func (d *TMap) AsInt64(key string) int64 {
 	switch ii := (*d)[key].(type) {
 	case int64:
 		return ii
+	case int:
+		return int64(ii)
 	case float64:
 		return int64(ii)
 	case json.Number:
@@ -67,11 +81,11 @@ func (d *TMap) AsInt64(key string) int64 {
 	return 0
 }
 
-// AsBase64 #
+// AsBase64 #base64url ohne Padding
 func (d *TMap) AsBase64() string {
 	bb, _ := json.Marshal(d)
 
-	return base64.RawStdEncoding.EncodeToString(bb)
+	return base64.RawURLEncoding.EncodeToString(bb)
 }
 
 // Assign #
@@ -86,17 +100,34 @@ func (t *JWToken) Clear() {
 	t.Claims = TMap{"iat": time.Now().UTC().Unix()}
 }
 
-// Encode #
+// Encode #HS256
 func (t *JWToken) Encode(key string) string {
-	shdr := t.Head.AsBase64()
-	spay := t.Claims.AsBase64()
-	sign := hmacSha256(shdr+"."+spay, key)
+	s, _ := t.Sign(hs256(key))
+	return s
+}
+
+// Sign #setzt alg und ggf. kid im Header
+func (t *JWToken) Sign(sg Signer) (string, error) {
+	t.Head["alg"] = sg.Alg()
+	if _, ok := t.Head["typ"]; !ok {
+		t.Head["typ"] = "JWT"
+	}
+
+	if ki, ok := sg.(interface{ KeyID() string }); ok && ki.KeyID() != "" {
+		t.Head["kid"] = ki.KeyID()
+	}
+
+	data := t.Head.AsBase64() + "." + t.Claims.AsBase64()
+	sig, err := sg.Sign([]byte(data))
+	if err != nil {
+		return "", err
+	}
 
-	t.RawData = shdr + "." + spay + "." + sign
-	return t.RawData
+	t.RawData = data + "." + base64.RawURLEncoding.EncodeToString(sig)
+	return t.RawData, nil
 }
 
-// Expired #
+// Expired #Sekunden seit iat, fuer exp/nbf siehe ValidateClaims
 func (t *JWToken) Expired() int64 {
 	return time.Now().UTC().Unix() - t.Claims.AsInt64("iat")
 }
@@ -122,10 +153,12 @@ func (t *JWToken) Parse(s string) error {
 		return ErrTokenNotValid
 	}
 
-	jHead, _ := json.Marshal(mHead)
-	cHead, _ := json.Marshal(&t.Head)
+	alg, _ := (*mHead)["alg"].(string)
+	if _, ok := algHash[alg]; !ok {
+		return ErrAlgUnsupported
+	}
 
-	if string(jHead) != string(cHead) {
+	if typ, ok := (*mHead)["typ"]; ok && !acceptTyp(typ) {
 		return ErrTokenNotValid
 	}
 
@@ -135,6 +168,7 @@ func (t *JWToken) Parse(s string) error {
 		return ErrTokenNotValid
 	}
 
+	t.Head = *mHead
 	t.Clear()
 	for k, v := range *m {
 		t.Claims[k] = v
@@ -144,24 +178,68 @@ func (t *JWToken) Parse(s string) error {
 	return nil
 }
 
-// Valid #
-func (t *JWToken) Valid(key string) error {
+func acceptTyp(typ interface{}) bool {
+	s, _ := typ.(string)
+	for _, a := range AcceptTyp {
+		if strings.EqualFold(s, a) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Valid #HS256, Signatur und Claims (o: optional)
+func (t *JWToken) Valid(key string, o ...*Validation) error {
+	return t.Verify(hs256(key), o...)
+}
+
+// Verify #alg im Header muss zum Verifier passen, danach ValidateClaims
+func (t *JWToken) Verify(v Verifier, o ...*Validation) error {
+	if err := t.verifySign(v); err != nil {
+		return err
+	}
+
+	var vo *Validation
+	if len(o) > 0 {
+		vo = o[0]
+	}
+
+	return t.ValidateClaims(vo)
+}
+
+func (t *JWToken) verifySign(v Verifier) error {
 	ss := strings.Split(t.RawData, ".")
 	if len(ss) != 3 {
 		return ErrTokenNotValid
 	}
 
+	if alg, _ := t.Head["alg"].(string); alg != v.Alg() {
+		return ErrAlgMismatch
+	}
+
+	data := []byte(ss[0] + "." + ss[1])
+
 	// Signature
-	sign := hmacSha256(ss[0]+"."+ss[1], key)
-	if sign != ss[2] {
-		return ErrTokenNotValid
+	sig, err := base64.RawURLEncoding.DecodeString(ss[2])
+	if err == nil && v.Verify(data, sig) == nil {
+		return nil
 	}
 
-	return nil
+	if LegacyHexSign && v.Alg() == "HS256" && len(ss[2]) == 64 {
+		if sig, err = hex.DecodeString(ss[2]); err == nil && v.Verify(data, sig) == nil {
+			return nil
+		}
+	}
+
+	return ErrTokenNotValid
 }
 
 func base64ToMap(s string) (*map[string]interface{}, error) {
-	ds, err := base64.RawStdEncoding.DecodeString(s)
+	ds, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil && LegacyHexSign {
+		ds, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -174,13 +252,6 @@ func base64ToMap(s string) (*map[string]interface{}, error) {
 	return m, nil
 }
 
-func hmacSha256(data string, key string) string {
-	// Create a new HMAC by defining the hash type and the key (as byte array)
-	h := hmac.New(sha256.New, []byte(key))
-
-	// Write Data to it
-	h.Write([]byte(data))
-
-	// Get result and encode as hexadecimal string
-	return hex.EncodeToString(h.Sum(nil))
+func hs256(key string) *hmacKey {
+	return &hmacKey{alg: "HS256", hash: crypto.SHA256, key: []byte(key)}
 }
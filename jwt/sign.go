@@ -0,0 +1,362 @@
+package jwt
+
+// ----------------------------------------------------------------------------------
+// sign.go (https://github.com/waldurbas/got): signer and verifier for JWT algorithms
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init: HS256/384/512, RS256/384/512, PS256/384/512, ES256/384, EdDSA
+//-----------------------------------------------------------------------------------
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+
+	_ "crypto/sha256" // SHA256 fuer crypto.Hash
+	_ "crypto/sha512" // SHA384, SHA512 fuer crypto.Hash
+)
+
+var (
+	// ErrAlgUnsupported #
+	ErrAlgUnsupported = errors.New("Algorithm not supported")
+	// ErrAlgMismatch #alg im Header passt nicht zum Verifier
+	ErrAlgMismatch = errors.New("Algorithm does not match key")
+	// ErrKeyType #Schluessel passt nicht zum Algorithmus
+	ErrKeyType = errors.New("Key type does not match algorithm")
+)
+
+// MinRSABits #min. Schluessellaenge fuer RS*/PS*
+var MinRSABits = 2048
+
+// Signer #
+type Signer interface {
+	Alg() string
+	Sign(data []byte) ([]byte, error)
+}
+
+// Verifier #
+type Verifier interface {
+	Alg() string
+	Verify(data []byte, sig []byte) error
+}
+
+var algHash = map[string]crypto.Hash{
+	"HS256": crypto.SHA256, "HS384": crypto.SHA384, "HS512": crypto.SHA512,
+	"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512,
+	"PS256": crypto.SHA256, "PS384": crypto.SHA384, "PS512": crypto.SHA512,
+	"ES256": crypto.SHA256, "ES384": crypto.SHA384,
+	"EdDSA": 0,
+}
+
+// NewSigner #key: []byte/string (HS*), *rsa.PrivateKey (RS*,PS*),
+// *ecdsa.PrivateKey (ES*), ed25519.PrivateKey (EdDSA)
+func NewSigner(alg string, key interface{}) (Signer, error) {
+	h, ok := algHash[alg]
+	if !ok {
+		return nil, ErrAlgUnsupported
+	}
+
+	switch alg[:2] {
+	case "HS":
+		b, err := hmacKeyBytes(key)
+		if err != nil {
+			return nil, err
+		}
+		return &hmacKey{alg: alg, hash: h, key: b}, nil
+
+	case "RS", "PS":
+		k, ok := key.(*rsa.PrivateKey)
+		if !ok || k.N.BitLen() < MinRSABits {
+			return nil, ErrKeyType
+		}
+		return &rsaSigner{rsaVerifier{alg: alg, hash: h, pub: &k.PublicKey}, k}, nil
+
+	case "ES":
+		k, ok := key.(*ecdsa.PrivateKey)
+		if !ok || k.Curve != esCurve(alg) {
+			return nil, ErrKeyType
+		}
+		return &ecSigner{ecVerifier{alg: alg, hash: h, pub: &k.PublicKey}, k}, nil
+
+	case "Ed":
+		k, ok := key.(ed25519.PrivateKey)
+		if !ok || len(k) != ed25519.PrivateKeySize {
+			return nil, ErrKeyType
+		}
+		return edSigner{k}, nil
+	}
+
+	return nil, ErrAlgUnsupported
+}
+
+// NewVerifier #key: []byte/string (HS*), *rsa.PublicKey (RS*,PS*),
+// *ecdsa.PublicKey (ES*), ed25519.PublicKey (EdDSA); private keys werden auch akzeptiert
+func NewVerifier(alg string, key interface{}) (Verifier, error) {
+	h, ok := algHash[alg]
+	if !ok {
+		return nil, ErrAlgUnsupported
+	}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		key = &k.PublicKey
+	case *ecdsa.PrivateKey:
+		key = &k.PublicKey
+	case ed25519.PrivateKey:
+		key = k.Public()
+	}
+
+	switch alg[:2] {
+	case "HS":
+		b, err := hmacKeyBytes(key)
+		if err != nil {
+			return nil, err
+		}
+		return &hmacKey{alg: alg, hash: h, key: b}, nil
+
+	case "RS", "PS":
+		k, ok := key.(*rsa.PublicKey)
+		if !ok || k.N.BitLen() < MinRSABits {
+			return nil, ErrKeyType
+		}
+		return &rsaVerifier{alg: alg, hash: h, pub: k}, nil
+
+	case "ES":
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok || k.Curve != esCurve(alg) {
+			return nil, ErrKeyType
+		}
+		return &ecVerifier{alg: alg, hash: h, pub: k}, nil
+
+	case "Ed":
+		k, ok := key.(ed25519.PublicKey)
+		if !ok || len(k) != ed25519.PublicKeySize {
+			return nil, ErrKeyType
+		}
+		return edVerifier{k}, nil
+	}
+
+	return nil, ErrAlgUnsupported
+}
+
+// ParsePEMKey #PKCS1, PKCS8, EC (SEC1) oder PKIX Public Key
+func ParsePEMKey(b []byte) (interface{}, error) {
+	blk, _ := pem.Decode(b)
+	if blk == nil {
+		return nil, errors.New("no PEM data")
+	}
+
+	switch blk.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(blk.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(blk.Bytes)
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(blk.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(blk.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(blk.Bytes)
+	case "CERTIFICATE":
+		c, err := x509.ParseCertificate(blk.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		return c.PublicKey, nil
+	}
+
+	return nil, errors.New("unknown PEM type: " + blk.Type)
+}
+
+func hmacKeyBytes(key interface{}) ([]byte, error) {
+	switch k := key.(type) {
+	case []byte:
+		if len(k) > 0 {
+			return k, nil
+		}
+	case string:
+		if len(k) > 0 {
+			return []byte(k), nil
+		}
+	}
+
+	return nil, ErrKeyType
+}
+
+func esCurve(alg string) elliptic.Curve {
+	if alg == "ES384" {
+		return elliptic.P384()
+	}
+
+	return elliptic.P256()
+}
+
+func digest(h crypto.Hash, data []byte) []byte {
+	x := h.New()
+	x.Write(data)
+	return x.Sum(nil)
+}
+
+//------------- HMAC ------------------------
+
+type hmacKey struct {
+	alg  string
+	hash crypto.Hash
+	key  []byte
+}
+
+func (k *hmacKey) Alg() string {
+	return k.alg
+}
+
+func (k *hmacKey) Sign(data []byte) ([]byte, error) {
+	h := hmac.New(k.hash.New, k.key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+func (k *hmacKey) Verify(data []byte, sig []byte) error {
+	mac, _ := k.Sign(data)
+	if !hmac.Equal(sig, mac) {
+		return ErrTokenNotValid
+	}
+
+	return nil
+}
+
+//------------- RSA ------------------------
+
+type rsaVerifier struct {
+	alg  string
+	hash crypto.Hash
+	pub  *rsa.PublicKey
+}
+
+type rsaSigner struct {
+	rsaVerifier
+	priv *rsa.PrivateKey
+}
+
+var pssOpt = &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+
+func (k *rsaVerifier) Alg() string {
+	return k.alg
+}
+
+func (k *rsaVerifier) Verify(data []byte, sig []byte) error {
+	var err error
+	if k.alg[0] == 'P' {
+		err = rsa.VerifyPSS(k.pub, k.hash, digest(k.hash, data), sig, pssOpt)
+	} else {
+		err = rsa.VerifyPKCS1v15(k.pub, k.hash, digest(k.hash, data), sig)
+	}
+
+	if err != nil {
+		return ErrTokenNotValid
+	}
+
+	return nil
+}
+
+func (k *rsaSigner) Sign(data []byte) ([]byte, error) {
+	if k.alg[0] == 'P' {
+		return rsa.SignPSS(rand.Reader, k.priv, k.hash, digest(k.hash, data), pssOpt)
+	}
+
+	return rsa.SignPKCS1v15(rand.Reader, k.priv, k.hash, digest(k.hash, data))
+}
+
+//------------- ECDSA ------------------------
+
+type ecVerifier struct {
+	alg  string
+	hash crypto.Hash
+	pub  *ecdsa.PublicKey
+}
+
+type ecSigner struct {
+	ecVerifier
+	priv *ecdsa.PrivateKey
+}
+
+func (k *ecVerifier) Alg() string {
+	return k.alg
+}
+
+func (k *ecVerifier) size() int {
+	return (k.pub.Curve.Params().BitSize + 7) / 8
+}
+
+// Verify #Signatur ist R || S mit fester Laenge
+func (k *ecVerifier) Verify(data []byte, sig []byte) error {
+	n := k.size()
+	if len(sig) != 2*n {
+		return ErrTokenNotValid
+	}
+
+	r := new(big.Int).SetBytes(sig[:n])
+	s := new(big.Int).SetBytes(sig[n:])
+	if !ecdsa.Verify(k.pub, digest(k.hash, data), r, s) {
+		return ErrTokenNotValid
+	}
+
+	return nil
+}
+
+func (k *ecSigner) Sign(data []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(rand.Reader, k.priv, digest(k.hash, data))
+	if err != nil {
+		return nil, err
+	}
+
+	n := k.size()
+	sig := make([]byte, 2*n)
+	r.FillBytes(sig[:n])
+	s.FillBytes(sig[n:])
+	return sig, nil
+}
+
+//------------- EdDSA ------------------------
+
+type edVerifier struct {
+	pub ed25519.PublicKey
+}
+
+type edSigner struct {
+	priv ed25519.PrivateKey
+}
+
+func (k edVerifier) Alg() string {
+	return "EdDSA"
+}
+
+func (k edVerifier) Verify(data []byte, sig []byte) error {
+	if !ed25519.Verify(k.pub, data, sig) {
+		return ErrTokenNotValid
+	}
+
+	return nil
+}
+
+func (k edSigner) Alg() string {
+	return "EdDSA"
+}
+
+func (k edSigner) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(k.priv, data), nil
+}
@@ -0,0 +1,312 @@
+package jwx
+
+// ----------------------------------------------------------------------------------
+// credential.go (https://github.com/waldurbas/got): password hashing and login throttling
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Throttle ohne Fail, auch ohne NewThrottle verwendbar
+// 2026.10.19 (wu) Throttle.Allow zaehlt den Versuch, Success nimmt ihn zurueck
+// 2026.10.19 (wu) Init: pbkdf2-sha256, scrypt, argon2id, Upgrade von Klartext/MD5, Throttle
+//-----------------------------------------------------------------------------------
+//
+// Format (PHC):
+//	$pbkdf2-sha256$i=600000$<salt>$<hash>
+//	$scrypt$ln=15,r=8,p=1$<salt>$<hash>
+//	$argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>
+// salt und hash base64 (std) ohne Padding; alles andere gilt als Klartext oder MD5 (hex)
+//
+//	ok, err := jwx.CheckPassword(u.Pwd, c.Pwd, func(h string) error { u.Pwd = h; return db.Save(u) })
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/waldurbas/got/cnv"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	// ErrHashFormat #kodierter Hash kann nicht gelesen werden
+	ErrHashFormat = errors.New("invalid password hash")
+
+	// DefaultHash #fuer HashPassword: "argon2id", "scrypt" oder "pbkdf2-sha256"
+	DefaultHash = "argon2id"
+
+	// PBKDF2Iter #
+	PBKDF2Iter = 600000
+	// ScryptLogN #N = 1<<ScryptLogN
+	ScryptLogN = 15
+	// ScryptR #
+	ScryptR = 8
+	// ScryptP #
+	ScryptP = 1
+	// Argon2Time #
+	Argon2Time uint32 = 3
+	// Argon2Memory #KiB
+	Argon2Memory uint32 = 64 * 1024
+	// Argon2Threads #
+	Argon2Threads uint8 = 4
+)
+
+const (
+	saltLen = 16
+	keyLen  = 32
+)
+
+var b64s = base64.RawStdEncoding
+
+// HashPassword #mit DefaultHash
+func HashPassword(pwd string) (string, error) {
+	return HashPasswordWith(DefaultHash, pwd)
+}
+
+// HashPasswordWith #alg: "argon2id", "scrypt" oder "pbkdf2-sha256", Parameter aus den Paket-Variablen
+func HashPasswordWith(alg string, pwd string) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	switch alg {
+	case "pbkdf2-sha256":
+		h := pbkdf2.Key([]byte(pwd), salt, PBKDF2Iter, keyLen, sha256.New)
+		return fmt.Sprintf("$pbkdf2-sha256$i=%d$%s$%s", PBKDF2Iter, b64s.EncodeToString(salt), b64s.EncodeToString(h)), nil
+
+	case "scrypt":
+		h, err := scrypt.Key([]byte(pwd), salt, 1<<ScryptLogN, ScryptR, ScryptP, keyLen)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s", ScryptLogN, ScryptR, ScryptP, b64s.EncodeToString(salt), b64s.EncodeToString(h)), nil
+
+	case "argon2id":
+		h := argon2.IDKey([]byte(pwd), salt, Argon2Time, Argon2Memory, Argon2Threads, keyLen)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, Argon2Memory, Argon2Time, Argon2Threads,
+			b64s.EncodeToString(salt), b64s.EncodeToString(h)), nil
+	}
+
+	return "", fmt.Errorf("hash %q not supported", alg)
+}
+
+// VerifyPassword #upgrade: Klartext, MD5, anderer Algorithmus oder schwaechere Parameter
+// als die aktuellen, dann sollte nach erfolgreichem Login neu gehasht werden
+func VerifyPassword(encoded string, pwd string) (ok bool, upgrade bool, err error) {
+	if !strings.HasPrefix(encoded, "$") {
+		return verifyLegacy(encoded, pwd), true, nil
+	}
+
+	// "", alg, params..., salt, hash
+	ss := strings.Split(encoded, "$")
+	if len(ss) < 5 {
+		return false, false, ErrHashFormat
+	}
+
+	salt, err1 := b64s.DecodeString(ss[len(ss)-2])
+	want, err2 := b64s.DecodeString(ss[len(ss)-1])
+	if err1 != nil || err2 != nil || len(want) == 0 {
+		return false, false, ErrHashFormat
+	}
+
+	var h []byte
+	switch ss[1] {
+	case "pbkdf2-sha256":
+		var iter int
+		if _, err := fmt.Sscanf(ss[2], "i=%d", &iter); err != nil || iter < 1 {
+			return false, false, ErrHashFormat
+		}
+		h = pbkdf2.Key([]byte(pwd), salt, iter, len(want), sha256.New)
+		upgrade = iter < PBKDF2Iter
+
+	case "scrypt":
+		var ln, r, p int
+		if _, err := fmt.Sscanf(ss[2], "ln=%d,r=%d,p=%d", &ln, &r, &p); err != nil || ln < 1 || ln > 30 {
+			return false, false, ErrHashFormat
+		}
+		if h, err = scrypt.Key([]byte(pwd), salt, 1<<ln, r, p, len(want)); err != nil {
+			return false, false, ErrHashFormat
+		}
+		upgrade = ln < ScryptLogN || r < ScryptR || p < ScryptP
+
+	case "argon2id":
+		var v int
+		var m, t uint32
+		var p uint8
+		if len(ss) != 6 {
+			return false, false, ErrHashFormat
+		}
+		if _, err := fmt.Sscanf(ss[2], "v=%d", &v); err != nil || v != argon2.Version {
+			return false, false, ErrHashFormat
+		}
+		if _, err := fmt.Sscanf(ss[3], "m=%d,t=%d,p=%d", &m, &t, &p); err != nil || t < 1 || p < 1 {
+			return false, false, ErrHashFormat
+		}
+		h = argon2.IDKey([]byte(pwd), salt, t, m, p, uint32(len(want)))
+		upgrade = m < Argon2Memory || t < Argon2Time
+
+	default:
+		return false, false, ErrHashFormat
+	}
+
+	ok = subtle.ConstantTimeCompare(h, want) == 1
+	return ok, upgrade || ss[1] != DefaultHash, nil
+}
+
+// verifyLegacy #Klartext oder cnv.Md5HexString
+func verifyLegacy(stored string, pwd string) bool {
+	if stored == "" {
+		return false
+	}
+
+	if len(stored) == 32 && isHex(stored) {
+		b := []byte(pwd)
+		if subtle.ConstantTimeCompare([]byte(strings.ToLower(stored)), []byte(cnv.Md5HexString(&b))) == 1 {
+			return true
+		}
+	}
+
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(pwd)) == 1
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckPassword #VerifyPassword, bei Erfolg und upgrade wird der neue Hash an save
+// uebergeben (save darf nil sein); ein Fehler von save verhindert den Login nicht
+func CheckPassword(encoded string, pwd string, save func(hash string) error) (bool, error) {
+	ok, upgrade, err := VerifyPassword(encoded, pwd)
+	if !ok || err != nil {
+		return false, err
+	}
+
+	if upgrade && save != nil {
+		if h, err := HashPassword(pwd); err == nil {
+			save(h)
+		}
+	}
+
+	return true, nil
+}
+
+//------------- Throttle ------------------------
+
+// Throttle #Login-Versuche je User und je IP in einem Zeitfenster;
+// ab MaxUser bzw. MaxIP wird bis zum Ende des Fensters gesperrt.
+// Allow zaehlt den Versuch sofort (parallele Versuche umgehen die Grenze nicht),
+// Success nimmt ihn zurueck; auch als &Throttle{...} verwendbar
+type Throttle struct {
+	MaxUser int // 0: ohne
+	MaxIP   int // 0: ohne
+	Window  time.Duration
+
+	mu   sync.Mutex
+	hits map[string]*hit
+	swp  time.Time
+}
+
+type hit struct {
+	n     int
+	start time.Time
+}
+
+// NewThrottle #z.B. NewThrottle(5, 50, 15*time.Minute)
+func NewThrottle(maxUser int, maxIP int, window time.Duration) *Throttle {
+	return &Throttle{MaxUser: maxUser, MaxIP: maxIP, Window: window, hits: make(map[string]*hit)}
+}
+
+// Allow #0, true: Versuch ist gezaehlt; sonst Wartezeit bis zum naechsten Versuch
+func (t *Throttle) Allow(user string, ip string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.hits == nil {
+		t.hits = make(map[string]*hit)
+	}
+
+	now := time.Now()
+	t.sweep(now)
+
+	var wait time.Duration
+	if d := t.blocked("u:"+user, t.MaxUser, now); d > wait {
+		wait = d
+	}
+	if d := t.blocked("i:"+ip, t.MaxIP, now); d > wait {
+		wait = d
+	}
+
+	if wait > 0 {
+		return wait, false
+	}
+
+	for _, k := range []string{"u:" + user, "i:" + ip} {
+		h, ok := t.hits[k]
+		if !ok || now.Sub(h.start) >= t.Window {
+			h = &hit{start: now}
+			t.hits[k] = h
+		}
+		h.n++
+	}
+
+	return 0, true
+}
+
+// Success #Versuch war erfolgreich: Zaehler des Users zuruecksetzen,
+// bei der IP nur diesen Versuch zuruecknehmen
+func (t *Throttle) Success(user string, ip string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.hits, "u:"+user)
+	if h, ok := t.hits["i:"+ip]; ok && h.n > 0 {
+		h.n--
+	}
+}
+
+func (t *Throttle) blocked(k string, max int, now time.Time) time.Duration {
+	h, ok := t.hits[k]
+	if max <= 0 || !ok || h.n < max {
+		return 0
+	}
+
+	if d := h.start.Add(t.Window).Sub(now); d > 0 {
+		return d
+	}
+
+	return 0
+}
+
+// sweep #abgelaufene Eintraege, hoechstens einmal je Fenster
+func (t *Throttle) sweep(now time.Time) {
+	if now.Sub(t.swp) < t.Window {
+		return
+	}
+
+	t.swp = now
+	for k, h := range t.hits {
+		if now.Sub(h.start) >= t.Window {
+			delete(t.hits, k)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package jwx
+
+// ----------------------------------------------------------------------------------
+// event.go (https://github.com/waldurbas/got): session events and janitor
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Hooks erst nach dem Unlock von m.mu
+// 2026.10.19 (wu) AddHook statt OnEvent, created/refreshed/expired/revoked, StartJanitor
+// 2026.10.19 (wu) Init: reuse-detected
+//-----------------------------------------------------------------------------------
+//
+//	jwx.AddHook(func(e *jwx.Event) { lgx.PrintFields("session", lgx.F("event", e.Type), lgx.F("sub", e.Sub)) })
+//	jwx.StartJanitor(ctx, time.Minute)
+
+import (
+	"context"
+	"time"
+
+	"github.com/waldurbas/got/lgx"
+)
+
+// EventType #
+type EventType string
+
+const (
+	// EventCreated #Login
+	EventCreated EventType = "created"
+	// EventRefreshed #neues Token-Paar (Refresh)
+	EventRefreshed EventType = "refreshed"
+	// EventExpired #abgelaufen, IdleTimeout oder MaxLifetime
+	EventExpired EventType = "expired"
+	// EventRevoked #Logout, TokenDelete, Revoke oder MaxSessions
+	EventRevoked EventType = "revoked"
+	// EventReuseDetected #bereits benutzter Refresh-Token, Session wurde widerrufen
+	EventReuseDetected EventType = "reuse-detected"
+)
+
+// JanitorInterval #Default fuer StartJanitor; ohne Janitor raeumt Login hoechstens so oft auf
+var JanitorInterval = time.Minute
+
+// Event #
+type Event struct {
+	Type EventType
+	Time time.Time
+	Key  string
+	Sub  string
+	App  string
+	UUID string
+	Info string
+}
+
+// AddHook #fn wird synchron aufgerufen und sollte nicht blockieren;
+// nie unter der Sperre des Managers, fn darf den Manager benutzen
+func (m *Manager) AddHook(fn func(e *Event)) {
+	m.hmu.Lock()
+	defer m.hmu.Unlock()
+
+	m.hooks = append(m.hooks, fn)
+}
+
+// AddHook #ueber Default-Manager
+func AddHook(fn func(e *Event)) {
+	Default().AddHook(fn)
+}
+
+// events #unter m.mu gesammelt, fire erst nach dem Unlock
+type events []*Event
+
+func (ee *events) add(typ EventType, lu *LoginUser, info string) {
+	*ee = append(*ee, newEvent(typ, lu, info))
+}
+
+func newEvent(typ EventType, lu *LoginUser, info string) *Event {
+	e := &Event{Type: typ, Time: time.Now(), Info: info}
+	if lu != nil {
+		e.Key, e.Sub, e.App, e.UUID = lu.Key, lu.Sub, lu.App, lu.UUID
+	}
+
+	return e
+}
+
+// emit #Hooks, nur ohne m.mu aufrufen
+func (m *Manager) emit(typ EventType, lu *LoginUser, info string) {
+	m.fire(newEvent(typ, lu, info))
+}
+
+// fire #Hooks, Sicherheits-Events werden immer geloggt
+func (m *Manager) fire(ee ...*Event) {
+	if len(ee) == 0 {
+		return
+	}
+
+	m.hmu.RLock()
+	hh := m.hooks
+	m.hmu.RUnlock()
+
+	for _, e := range ee {
+		if e.Type == EventReuseDetected {
+			lgx.PrintfError("jwx: refresh token reuse, session revoked: key=%s sub=%s app=%s %s", e.Key, e.Sub, e.App, e.Info)
+		}
+
+		for _, fn := range hh {
+			fn(e)
+		}
+	}
+}
+
+// StartJanitor #Sweep alle d (<= 0: JanitorInterval) bis ctx beendet ist
+func (m *Manager) StartJanitor(ctx context.Context, d time.Duration) {
+	if d <= 0 {
+		d = JanitorInterval
+	}
+
+	go func() {
+		tk := time.NewTicker(d)
+		defer tk.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-tk.C:
+				if _, err := m.Sweep(); err != nil {
+					lgx.PrintError("jwx.janitor:", err)
+				}
+			}
+		}
+	}()
+}
+
+// StartJanitor #ueber Default-Manager
+func StartJanitor(ctx context.Context, d time.Duration) {
+	Default().StartJanitor(ctx, d)
+}
+
+// maybeSweep #ohne Janitor: hoechstens alle JanitorInterval
+func (m *Manager) maybeSweep(now int64) {
+	m.hmu.RLock()
+	last := m.lastSweep
+	m.hmu.RUnlock()
+
+	if now-last >= int64(JanitorInterval/time.Second) {
+		m.Sweep()
+	}
+}
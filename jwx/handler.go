@@ -0,0 +1,301 @@
+package jwx
+
+// ----------------------------------------------------------------------------------
+// handler.go (https://github.com/waldurbas/got): http handlers for login, refresh, logout
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Throttle fuer /login
+// 2026.10.19 (wu) Init: POST /login, /refresh, /logout, GET /sessions
+//-----------------------------------------------------------------------------------
+//
+//	h := &jwx.Handlers{SignKey: key, Check: checkUser, Admin: isAdmin}
+//	h.Register(mux, "/auth")
+//
+// Fehler immer als JSON: {"message": "..."}
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/waldurbas/got/htx"
+	"github.com/waldurbas/got/jwt"
+)
+
+// ErrInvalidCredentials #vom CredentialChecker, fuehrt zu 401
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// maxBody #max. Groesse des Request-Body
+const maxBody = 1 << 16
+
+// Credentials #Body von POST /login
+type Credentials struct {
+	User string `json:"user"`
+	Pwd  string `json:"pwd"`
+	App  string `json:"app,omitempty"`
+}
+
+// Identity #Ergebnis des CredentialChecker
+type Identity struct {
+	UUID   string
+	App    string
+	URL    string
+	Claims map[string]interface{} // Claims fuer den Refresh-Token (z.B. role)
+}
+
+// CredentialChecker #prueft die Login-Daten; ErrInvalidCredentials: 401, sonstige Fehler: 500
+type CredentialChecker func(r *http.Request, c *Credentials) (*Identity, error)
+
+// TokenResponse #Antwort von /login und /refresh
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Sub          string `json:"sub"`
+}
+
+// SessionInfo #Eintrag von GET /sessions, ohne Token
+type SessionInfo struct {
+	Sub     string `json:"sub"`
+	App     string `json:"app"`
+	UUID    string `json:"uuid"`
+	Expired int64  `json:"expired"`
+}
+
+// Handlers #
+type Handlers struct {
+	M       *Manager // nil: Default
+	SignKey string
+	Check   CredentialChecker
+	Admin   func(r *http.Request) bool // fuer GET /sessions, nil: kein Zugriff
+
+	Throttle *Throttle                    // Fehlversuche bei /login, nil: ohne
+	ClientIP func(r *http.Request) string // IP fuer Throttle, nil: RemoteAddr
+}
+
+func (h *Handlers) clientIP(r *http.Request) string {
+	if h.ClientIP != nil {
+		return h.ClientIP(r)
+	}
+
+	if ip, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return ip
+	}
+
+	return r.RemoteAddr
+}
+
+func (h *Handlers) manager() *Manager {
+	if h.M != nil {
+		return h.M
+	}
+
+	return Default()
+}
+
+// Register #prefix+"/login", "/refresh", "/logout", "/sessions"
+func (h *Handlers) Register(mux *http.ServeMux, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	mux.Handle(prefix+"/login", h.Login())
+	mux.Handle(prefix+"/refresh", h.Refresh())
+	mux.Handle(prefix+"/logout", h.Logout())
+	mux.Handle(prefix+"/sessions", h.Sessions())
+}
+
+// Login #POST {"user","pwd","app"}
+func (h *Handlers) Login() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var c Credentials
+		if !readJSON(w, r, "jwx.login", &c) {
+			return
+		}
+
+		if c.User == "" || h.Check == nil {
+			htx.WriteResponseMsg("jwx.login", w, http.StatusUnauthorized, ErrInvalidCredentials.Error())
+			return
+		}
+
+		ip := h.clientIP(r)
+		if h.Throttle != nil {
+			if d, ok := h.Throttle.Allow(c.User, ip); !ok {
+				w.Header().Set("Retry-After", strconv.Itoa(int(d/time.Second)+1))
+				htx.WriteResponseMsg("jwx.login", w, http.StatusTooManyRequests, "too many login attempts")
+				return
+			}
+		}
+
+		id, err := h.Check(r, &c)
+		if err == ErrInvalidCredentials {
+			htx.WriteResponseMsg("jwx.login", w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if err != nil || id == nil {
+			htx.WriteResponseMsg("jwx.login", w, http.StatusInternalServerError, "login failed")
+			return
+		}
+
+		if h.Throttle != nil {
+			h.Throttle.Success(c.User, ip)
+		}
+
+		if id.App == "" {
+			id.App = c.App
+		}
+
+		lu := &LoginUser{App: id.App, UUID: id.UUID, URL: id.URL, SignKey: h.SignKey}
+		lu, err = h.manager().Login(uuid.New().String(), lu, id.Claims)
+		if err != nil {
+			htx.WriteResponseMsg("jwx.login", w, http.StatusInternalServerError, "login failed")
+			return
+		}
+
+		htx.WriteResponse(w, http.StatusOK, tokenResponse(lu))
+	})
+}
+
+// Refresh #POST {"refresh_token"}, liefert ein neues Paar
+func (h *Handlers) Refresh() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		var b struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		if !readJSON(w, r, "jwx.refresh", &b) {
+			return
+		}
+
+		lu, err := h.manager().Refresh(b.RefreshToken)
+		switch {
+		case err == ErrRefreshReused:
+			htx.WriteResponseMsg("jwx.refresh", w, http.StatusUnauthorized, err.Error())
+			return
+		case err == jwt.ErrTokenExpired:
+			htx.WriteResponseMsg("jwx.refresh", w, http.StatusUnauthorized, "refresh token expired")
+			return
+		case err != nil:
+			htx.WriteResponseMsg("jwx.refresh", w, http.StatusUnauthorized, "invalid refresh token")
+			return
+		}
+
+		htx.WriteResponse(w, http.StatusOK, tokenResponse(lu))
+	})
+}
+
+// Logout #POST mit Authorization: Bearer <access_token>
+func (h *Handlers) Logout() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+
+		m := h.manager()
+
+		t := jwt.New()
+		if t.Parse(BearerToken(r)) != nil || t.Valid(h.SignKey) != nil {
+			htx.WriteResponseMsg("jwx.logout", w, http.StatusUnauthorized, "invalid access token")
+			return
+		}
+
+		lu, err := m.Store.GetBySub(t.Claims.AsString("sub"))
+		if err != nil {
+			htx.WriteResponseMsg("jwx.logout", w, http.StatusUnauthorized, ErrNoSession.Error())
+			return
+		}
+
+		if err := m.Revoke(lu.Key); err != nil {
+			htx.WriteResponseMsg("jwx.logout", w, http.StatusInternalServerError, "logout failed")
+			return
+		}
+
+		htx.WriteResponse(w, http.StatusOK, map[string]interface{}{"message": "logged out"})
+	})
+}
+
+// Sessions #GET ?app=..., ohne app: alle
+func (h *Handlers) Sessions() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+
+		if h.Admin == nil || !h.Admin(r) {
+			htx.WriteResponseMsg("jwx.sessions", w, http.StatusForbidden, "forbidden")
+			return
+		}
+
+		m := h.manager()
+		now := time.Now().Unix()
+
+		app := r.URL.Query().Get("app")
+		ss := []SessionInfo{}
+
+		m.Store.Range(func(k string, v *LoginUser) bool {
+			if (app == "" || v.App == app) && alive(m.Policy(v.App), v, now) == "" {
+				ss = append(ss, SessionInfo{Sub: v.Sub, App: v.App, UUID: v.UUID, Expired: v.Expired})
+			}
+			return true
+		})
+
+		htx.WriteResponse(w, http.StatusOK, ss)
+	})
+}
+
+// BearerToken #Token aus "Authorization: Bearer ...", "" wenn nicht vorhanden
+func BearerToken(r *http.Request) string {
+	s := r.Header.Get("Authorization")
+	if len(s) > 7 && strings.EqualFold(s[:7], "bearer ") {
+		return strings.TrimSpace(s[7:])
+	}
+
+	return ""
+}
+
+func tokenResponse(lu *LoginUser) *TokenResponse {
+	return &TokenResponse{
+		AccessToken:  lu.Access.RawData,
+		RefreshToken: lu.Refresh.RawData,
+		TokenType:    "Bearer",
+		ExpiresIn:    lu.Access.Claims.AsInt64("exp") - time.Now().Unix(),
+		Sub:          lu.Sub,
+	}
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+
+	w.Header().Set("Allow", method)
+	htx.WriteResponse(w, http.StatusMethodNotAllowed, map[string]interface{}{"message": "method not allowed"})
+	return false
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, from string, v interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(v); err != nil {
+		htx.WriteResponseMsg(from, w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+
+	return true
+}
@@ -11,6 +11,20 @@ package jwx
 // ----------------------------------------------------------------------------------
 // HISTORY
 //-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) GenerateTokenPair liefert mit MemStore wieder die Session im Store
+// 2026.10.19 (wu) Revoke, Sweep, TokenDelete unter m.mu (kein Put nach Delete durch Refresh)
+// 2026.10.19 (wu) Default/SetDefault mit Sperre
+// 2026.10.19 (wu) GenerateTokenPair: App und UUID aus den Claims
+// 2026.10.19 (wu) GetRefreshToken prueft den Access-Token, CheckUserToken ohne Rotation
+// 2026.10.19 (wu) Login mit Kopie der Vorlage, Pwd nicht als JSON
+// 2026.10.19 (wu) Hooks (event.go), Janitor statt Sweep in CheckUserToken/GetMemToken
+// 2026.10.19 (wu) Policy je App (policy.go), LoginUser.Created/LastSeen
+// 2026.10.19 (wu) Login, Revoke, HTTP-Handler (handler.go)
+// 2026.10.19 (wu) Refresh-Token nur einmal gueltig (Rotation), Wiederverwendung widerruft die Session
+// 2026.10.19 (wu) Manager mit Store statt globaler Maps, Paket-Funktionen ueber Default
+// 2026.10.19 (wu) RefreshKey: Refresh-Token als JWE
+// 2026.10.19 (wu) RawToken2Sub ohne panic
+// 2026.10.19 (wu) CheckUserToken mit jwt.ValidateClaims, Access-Token mit exp
 // 2020.09.06 (wu) Init
 //-----------------------------------------------------------------------------------
 
@@ -20,31 +34,32 @@ import (
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/waldurbas/got/jwt"
 )
 
+// ErrRefreshReused #Refresh-Token wurde bereits benutzt, Session ist widerrufen
+var ErrRefreshReused = errors.New("refresh token reused")
+
+// maxUsed #gemerkte jti bereits benutzter Refresh-Token je Session
+const maxUsed = 32
+
 var (
 	// DurationAccessToken #
 	DurationAccessToken = 15 * time.Minute
 	// DurationRefreshToken #
 	DurationRefreshToken = 16 * time.Hour
 
-	//Key: user
-	usersToken = make(map[string]*LoginUser, 100)
-
-	//Key: sub from token
-	usersSub = make(map[string]*LoginUser, 100)
-
-	// subID
-	subID = time.Now().UTC().Unix()
+	// RefreshKey #oct-Schluessel (32 Bytes): Refresh-Token wird signiert und verschluesselt (JWE)
+	RefreshKey *jwt.JWK
 
-	// mutex for locking
-	mutex = &sync.Mutex{}
+	stdMu sync.RWMutex
+	std   = NewManager(nil)
 )
 
 // LoginUser #
 type LoginUser struct {
-	Pwd     string
+	Pwd     string `json:"-"` // nicht im FileStore
 	Expired int64
 	App     string
 	UUID    string
@@ -54,6 +69,11 @@ type LoginUser struct {
 	SignKey string
 	Access  *jwt.JWToken
 	Refresh *jwt.JWToken
+
+	Key      string   // Login-Key im Store
+	Used     []string // jti bereits benutzter Refresh-Token (Token-Familie)
+	Created  int64    // Login
+	LastSeen int64    // letzte Aktivitaet, nur mit Policy.IdleTimeout
 }
 
 // MemToken #
@@ -64,212 +84,515 @@ type MemToken struct {
 	Refresh string
 }
 
-func incSubID() int64 {
-	mutex.Lock()
-	defer mutex.Unlock()
+// Manager #Sessions in einem Store; leere Felder: Paket-Variablen
+type Manager struct {
+	Store      Store
+	AccessTTL  time.Duration // 0: DurationAccessToken, Policy.AccessTTL hat Vorrang
+	RefreshTTL time.Duration // 0: DurationRefreshToken, Policy.RefreshTTL hat Vorrang
+	RefreshKey *jwt.JWK      // nil: RefreshKey
+
+	mu    sync.Mutex
+	subID int64
+
+	hmu       sync.RWMutex
+	hooks     []func(e *Event)
+	lastSweep int64
 
-	subID++
-	return subID
+	pmu      sync.RWMutex
+	policies map[string]*Policy
 }
 
-// GenerateTokenPair #
-func GenerateTokenPair(m *map[string]interface{}, key string, signKey string) (*LoginUser, error) {
-	sub := strconv.FormatInt(incSubID(), 10)
-	sub = sub[5:] + "." + sub[:5]
+// NewManager #s == nil: MemStore
+func NewManager(s Store) *Manager {
+	if s == nil {
+		s = NewMemStore()
+	}
 
-	at := jwt.New()
-	at.Claims["sub"] = sub
+	return &Manager{Store: s, subID: time.Now().UTC().Unix()}
+}
 
-	exp := time.Now().Add(DurationRefreshToken).UTC().Unix()
+// Default #Manager der Paket-Funktionen
+func Default() *Manager {
+	stdMu.RLock()
+	defer stdMu.RUnlock()
 
-	rt := jwt.New()
-	rt.Claims.Assign(m)
-	rt.Claims["sub"] = sub
-	rt.Claims["exp"] = exp
+	return std
+}
 
-	at.Encode(signKey)
-	rt.Encode(signKey)
+// SetDefault #setzt den Manager der Paket-Funktionen, liefert den bisherigen
+func SetDefault(m *Manager) *Manager {
+	stdMu.Lock()
+	defer stdMu.Unlock()
+
+	old := std
+	std = m
+	return old
+}
 
-	lu := &LoginUser{
-		Expired: exp,
-		Access:  at,
-		Refresh: rt,
-		Sub:     sub,
-		SignKey: signKey,
+func (lu *LoginUser) clone() *LoginUser {
+	c := *lu
+	c.Used = append([]string(nil), lu.Used...)
+	return &c
+}
+
+func (lu *LoginUser) isUsed(jti string) bool {
+	return contains(lu.Used, jti)
+}
+
+func (m *Manager) refreshKey() *jwt.JWK {
+	if m.RefreshKey != nil {
+		return m.RefreshKey
 	}
 
-	mutex.Lock()
-	defer mutex.Unlock()
+	return RefreshKey
+}
+
+func (m *Manager) incSubID() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	usersToken[key] = lu
-	usersSub[sub] = lu
+	m.subID++
+	return m.subID
+}
+
+// GenerateTokenPair #Session unter key, App und UUID aus den Claims "app" und "uuid"
+// (Policy der App gilt). Mit MemStore wie bisher die Session im Store: App, UUID, URL
+// und Pwd duerfen direkt danach gesetzt werden (nicht nebenlaeufig zu anderen Aufrufen);
+// mit anderen Stores nur eine Kopie, dort Login mit Vorlage verwenden
+func (m *Manager) GenerateTokenPair(cl *map[string]interface{}, key string, signKey string) (*LoginUser, error) {
+	var c jwt.TMap
+	if cl != nil {
+		c = *cl
+	}
+
+	lu, err := m.Login(key, &LoginUser{App: c.AsString("app"), UUID: c.AsString("uuid"), SignKey: signKey}, c)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := m.Store.(*MemStore); ok {
+		s.share(key, lu)
+	}
 
 	return lu, nil
 }
 
-// Validate #
-func Validate(headToken string, signKey string) (map[string]interface{}, error) {
-	tkn := jwt.New()
-	if err := tkn.Parse(headToken); err != nil {
+// Login #neue Session unter key, lu: Vorlage mit App, UUID, URL und SignKey,
+// cl: Claims fuer den Refresh-Token; Policy der App gilt.
+// Liefert eine Kopie, der Store wird nur ueber den Manager geaendert
+func (m *Manager) Login(key string, lu *LoginUser, cl map[string]interface{}) (*LoginUser, error) {
+	lu = lu.clone()
+	sub := strconv.FormatInt(m.incSubID(), 10)
+	sub = sub[5:] + "." + sub[:5]
+
+	lu.Sub = sub
+	lu.Used = nil
+	lu.Created = time.Now().Unix()
+	lu.LastSeen = lu.Created
+
+	m.maybeSweep(lu.Created)
+	m.limitSessions(m.Policy(lu.App), lu)
+
+	if err := m.newPair(lu, cl); err != nil {
 		return nil, err
 	}
 
-	if err := tkn.Valid(signKey); err != nil {
+	if err := m.Store.Put(key, lu); err != nil {
 		return nil, err
 	}
 
-	m := make(map[string]interface{}, len(tkn.Claims))
+	m.emit(EventCreated, lu, "")
+	return lu, nil
+}
 
-	for k, v := range tkn.Claims {
-		m[k] = v
-	}
+// Revoke #Session unter key loeschen
+func (m *Manager) Revoke(key string) error {
+	return m.revoke(key, "")
+}
 
-	return m, nil
+func (m *Manager) revoke(key string, info string) error {
+	var ev events
+	defer func() { m.fire(ev...) }()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.remove(key, info, &ev)
 }
 
-// RawToken2Sub #
-func RawToken2Sub(headToken string) string {
-	tkn := jwt.New()
-	if tkn.Parse(headToken) != nil {
-		return ""
+// remove #Session unter key widerrufen, unter m.mu: Event in ev
+func (m *Manager) remove(key string, info string, ev *events) error {
+	lu, err := m.Store.Get(key)
+	if err != nil {
+		return nil
+	}
+
+	if err := m.Store.Delete(key); err != nil {
+		return err
 	}
 
-	return tkn.Claims["sub"].(string)
+	ev.add(EventRevoked, lu, info)
+	return nil
 }
 
-// CheckElapsedToken #
-func CheckElapsedToken(withLock bool) {
-	if withLock {
-		mutex.Lock()
-		defer mutex.Unlock()
+// expire #Session wegen Policy oder Ablauf loeschen, unter m.mu: Event in ev
+func (m *Manager) expire(lu *LoginUser, info string, ev *events) {
+	if m.Store.Delete(lu.Key) == nil {
+		ev.add(EventExpired, lu, info)
 	}
+}
 
-	cTime := time.Now().Unix()
-	for k, v := range usersToken {
-		if (v.Expired - cTime) < 1 {
-			delete(usersSub, v.Sub)
-			delete(usersToken, k)
+// newPair #neuer Access- und Refresh-Token (mit neuer jti), cl: Claims fuer den Refresh-Token
+func (m *Manager) newPair(lu *LoginUser, cl map[string]interface{}) error {
+	p := m.Policy(lu.App)
+	exp := capExp(p, lu, time.Now().Add(m.refreshTTL(p)).UTC().Unix())
+
+	at := m.newAccess(p, lu, exp)
+
+	rt := jwt.New()
+	for k, v := range cl {
+		if k != "iat" && k != "exp" && k != "jti" {
+			rt.Claims[k] = v
 		}
 	}
+	rt.Claims["sub"] = lu.Sub
+	rt.Claims["jti"] = uuid.New().String()
+	rt.Claims["exp"] = exp
+
+	rt.Encode(lu.SignKey)
+
+	if k := m.refreshKey(); k != nil {
+		s, err := jwt.Encrypt([]byte(rt.RawData), "", k, jwt.TMap{"cty": "JWT"})
+		if err != nil {
+			return err
+		}
+		rt.RawData = s
+	}
+
+	lu.Expired = exp
+	lu.Access = at
+	lu.Refresh = rt
+	return nil
 }
 
-// TokenDelete #
-func TokenDelete(uid string) {
-	mutex.Lock()
-	defer mutex.Unlock()
-
-	if uid == "all" {
-		usersToken = make(map[string]*LoginUser, 100)
-		usersSub = make(map[string]*LoginUser, 100)
-	} else {
-		for k, v := range usersToken {
-			if v.UUID == uid {
-				delete(usersSub, v.Sub)
-				delete(usersToken, k)
-			}
+// newAccess #Access-Token, laeuft spaetestens mit exp (Refresh-Token) ab
+func (m *Manager) newAccess(p *Policy, lu *LoginUser, exp int64) *jwt.JWToken {
+	at := jwt.New()
+	at.Claims["sub"] = lu.Sub
+	at.SetExpiry(m.accessTTL(p))
+	if at.Claims.AsInt64("exp") > exp {
+		at.Claims["exp"] = exp
+	}
+
+	at.Encode(lu.SignKey)
+	return at
+}
+
+// rotate #alter Refresh-Token wird ungueltig, neues Paar
+func (m *Manager) rotate(lu *LoginUser) error {
+	if jti := lu.Refresh.Claims.AsString("jti"); jti != "" {
+		lu.Used = append(lu.Used, jti)
+		if len(lu.Used) > maxUsed {
+			lu.Used = lu.Used[len(lu.Used)-maxUsed:]
 		}
 	}
+
+	return m.newPair(lu, lu.Refresh.Claims)
 }
 
-// CheckUserToken #
-func CheckUserToken(kk string) *LoginUser {
-	mutex.Lock()
-	defer mutex.Unlock()
+// Refresh #Refresh-Token (auch JWE) gegen ein neues Paar tauschen, der alte wird ungueltig.
+// Ein bereits benutzter Refresh-Token widerruft die Session: ErrRefreshReused
+func (m *Manager) Refresh(raw string) (*LoginUser, error) {
+	t := jwt.New()
+	if jwt.IsEncrypted(raw) {
+		k := m.refreshKey()
+		if k == nil {
+			return nil, jwt.ErrTokenNotValid
+		}
+		if err := t.ParseEncrypted(raw, k); err != nil {
+			return nil, err
+		}
+	} else if err := t.Parse(raw); err != nil {
+		return nil, err
+	}
 
-	CheckElapsedToken(false)
+	var ev events
+	defer func() { m.fire(ev...) }()
 
-	lu, found := usersToken[kk]
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	if !found {
-		return nil
+	lu, err := m.Store.GetBySub(t.Claims.AsString("sub"))
+	if err != nil {
+		return nil, err
 	}
 
-	// check Access-Token 15Min.
-	if lu.Access.Expired() > int64(DurationAccessToken) {
-		if lu.Refresh.Expired() > int64(DurationRefreshToken) {
-			return nil
+	if err := t.Valid(lu.SignKey); err != nil {
+		return nil, err
+	}
+
+	p := m.Policy(lu.App)
+	now := time.Now().Unix()
+	if msg := alive(p, lu, now); msg != "" {
+		m.expire(lu, msg, &ev)
+		return nil, jwt.ErrTokenExpired
+	}
+
+	jti := t.Claims.AsString("jti")
+	if jti == "" || jti != lu.Refresh.Claims.AsString("jti") {
+		if jti != "" && lu.isUsed(jti) {
+			m.Store.Delete(lu.Key)
+			ev.add(EventReuseDetected, lu, "jti="+jti)
+			return nil, ErrRefreshReused
 		}
+		return nil, jwt.ErrTokenNotValid
+	}
 
-		tkn := jwt.New()
-		tkn.Claims["sub"] = lu.Sub
-		tkn.Encode(lu.SignKey)
-		lu.Access = tkn
+	if err := m.rotate(lu); err != nil {
+		return nil, err
 	}
 
-	return &LoginUser{
-		Pwd:     lu.Pwd,
-		Expired: lu.Expired,
-		App:     lu.App,
-		UUID:    lu.UUID,
-		URL:     lu.URL,
-		Sub:     lu.Sub,
-		SignKey: lu.SignKey,
-		Access:  lu.Access,
-		Refresh: lu.Refresh,
+	lu.LastSeen = now
+	if err := m.Store.Put(lu.Key, lu); err != nil {
+		return nil, err
 	}
+
+	ev.add(EventRefreshed, lu, "")
+	return lu.clone(), nil
 }
 
-// GetMemToken #
-func GetMemToken(app string) []MemToken {
-	mutex.Lock()
-	defer mutex.Unlock()
+// Sweep #loescht abgelaufene Sessions, mit Policies auch bei IdleTimeout und MaxLifetime
+func (m *Manager) Sweep() (int, error) {
+	now := time.Now().Unix()
+	m.hmu.Lock()
+	m.lastSweep = now
+	m.hmu.Unlock()
 
-	CheckElapsedToken(false)
+	var ev events
+	defer func() { m.fire(ev...) }()
 
-	ma := []MemToken{}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	for _, v := range usersToken {
-		if v.App == app || app == "all" {
-			m := MemToken{
-				App:     v.App,
-				Expired: v.Expired,
-				UUID:    v.UUID,
-				Refresh: v.Refresh.RawData,
-			}
-			ma = append(ma, m)
+	ll, err := m.Store.Sweep(now)
+	for _, lu := range ll {
+		ev.add(EventExpired, lu, "session expired")
+	}
+
+	n := len(ll)
+	if err != nil {
+		return n, err
+	}
+
+	m.pmu.RLock()
+	np := len(m.policies)
+	m.pmu.RUnlock()
+
+	if np == 0 {
+		return n, nil
+	}
+
+	var dd []*LoginUser
+	var msgs []string
+	m.Store.Range(func(k string, v *LoginUser) bool {
+		if msg := alive(m.Policy(v.App), v, now); msg != "" {
+			dd = append(dd, v)
+			msgs = append(msgs, msg)
+		}
+		return true
+	})
+
+	for i, lu := range dd {
+		if err := m.Store.Delete(lu.Key); err != nil {
+			return n, err
 		}
+		ev.add(EventExpired, lu, msgs[i])
+		n++
 	}
 
-	return ma
+	return n, nil
 }
 
-func subToLoginUser(kk string) *LoginUser {
-	mutex.Lock()
-	defer mutex.Unlock()
+// TokenDelete #Sessions mit UUID uid, "all": alle
+func (m *Manager) TokenDelete(uid string) error {
+	var ev events
+	defer func() { m.fire(ev...) }()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	if lu, found := usersSub[kk]; found {
+	var kk []string
 
-		return &LoginUser{
-			Pwd:     lu.Pwd,
-			Expired: lu.Expired,
-			App:     lu.App,
-			UUID:    lu.UUID,
-			URL:     lu.URL,
-			Sub:     lu.Sub,
-			SignKey: lu.SignKey,
-			Access:  lu.Access,
-			Refresh: lu.Refresh,
+	m.Store.Range(func(k string, v *LoginUser) bool {
+		if uid == "all" || v.UUID == uid {
+			kk = append(kk, k)
+		}
+		return true
+	})
+
+	for _, k := range kk {
+		if err := m.remove(k, "TokenDelete "+uid, &ev); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-// GetRefreshToken #
-func GetRefreshToken(accessToken string) (*jwt.JWToken, error) {
-	sub := RawToken2Sub(accessToken)
-	if sub == "" {
+// CheckUserToken #Session unter kk, bei abgelaufenem Access-Token wird nur dieser neu erstellt;
+// der Refresh-Token des Clients bleibt gueltig (Rotation nur ueber Refresh)
+func (m *Manager) CheckUserToken(kk string) *LoginUser {
+	var ev events
+	defer func() { m.fire(ev...) }()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lu, err := m.Store.Get(kk)
+	if err != nil {
+		return nil
+	}
+
+	p := m.Policy(lu.App)
+	now := time.Now().Unix()
+	if msg := alive(p, lu, now); msg != "" {
+		m.expire(lu, msg, &ev)
+		return nil
+	}
+
+	changed := false
+	if p.IdleTimeout > 0 && lu.LastSeen != now {
+		lu.LastSeen = now
+		changed = true
+	}
+
+	// check Access-Token
+	if lu.Access.ValidateClaims(nil) != nil {
+		if lu.Refresh.ValidateClaims(nil) != nil {
+			return nil
+		}
+
+		lu.Access = m.newAccess(p, lu, lu.Expired)
+		changed = true
+	}
+
+	if changed && m.Store.Put(kk, lu) != nil {
+		return nil
+	}
+
+	return lu.clone()
+}
+
+// GetMemToken #Sessions der App, "all": alle; abgelaufene werden uebersprungen
+func (m *Manager) GetMemToken(app string) []MemToken {
+	now := time.Now().Unix()
+	ma := []MemToken{}
+
+	m.Store.Range(func(k string, v *LoginUser) bool {
+		if (v.App == app || app == "all") && alive(m.Policy(v.App), v, now) == "" {
+			ma = append(ma, MemToken{
+				App:     v.App,
+				Expired: v.Expired,
+				UUID:    v.UUID,
+				Refresh: v.Refresh.RawData,
+			})
+		}
+		return true
+	})
+
+	return ma
+}
+
+// GetRefreshToken #aktueller (noch nicht benutzter) Refresh-Token der Session,
+// nur gegen einen gueltigen Access-Token (Signatur mit SignKey, exp)
+func (m *Manager) GetRefreshToken(accessToken string) (*jwt.JWToken, error) {
+	t := jwt.New()
+	if t.Parse(accessToken) != nil {
 		return nil, errors.New("getRToken.Raw")
 	}
 
-	lu := subToLoginUser(sub)
-	if lu == nil {
+	// Refresh-Token haben eine jti, Access-Token nicht
+	if _, ok := t.Claims["jti"]; ok {
+		return nil, jwt.ErrTokenNotValid
+	}
+
+	lu, err := m.Store.GetBySub(t.Claims.AsString("sub"))
+	if err != nil {
 		return nil, errors.New("getRToken.Sub")
 	}
 
+	if err := t.Valid(lu.SignKey); err != nil {
+		return nil, err
+	}
+
 	if time.Now().UTC().Unix() > lu.Expired {
 		return nil, jwt.ErrTokenExpired
 	}
 
 	return lu.Refresh, nil
 }
+
+// GenerateTokenPair #ueber Default-Manager, Ergebnis ist die Session im Store (siehe Manager.GenerateTokenPair)
+func GenerateTokenPair(m *map[string]interface{}, key string, signKey string) (*LoginUser, error) {
+	return Default().GenerateTokenPair(m, key, signKey)
+}
+
+// Validate #
+func Validate(headToken string, signKey string) (map[string]interface{}, error) {
+	tkn := jwt.New()
+	if err := tkn.Parse(headToken); err != nil {
+		return nil, err
+	}
+
+	if err := tkn.Valid(signKey); err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]interface{}, len(tkn.Claims))
+
+	for k, v := range tkn.Claims {
+		m[k] = v
+	}
+
+	return m, nil
+}
+
+// RawToken2Sub #
+func RawToken2Sub(headToken string) string {
+	tkn := jwt.New()
+	if tkn.Parse(headToken) != nil {
+		return ""
+	}
+
+	return tkn.Claims.AsString("sub")
+}
+
+// CheckElapsedToken #ueber Default-Manager, withLock ist ohne Bedeutung (Store sperrt selbst)
+func CheckElapsedToken(withLock bool) {
+	Default().Sweep()
+}
+
+// TokenDelete #ueber Default-Manager
+func TokenDelete(uid string) {
+	Default().TokenDelete(uid)
+}
+
+// CheckUserToken #ueber Default-Manager
+func CheckUserToken(kk string) *LoginUser {
+	return Default().CheckUserToken(kk)
+}
+
+// GetMemToken #ueber Default-Manager
+func GetMemToken(app string) []MemToken {
+	return Default().GetMemToken(app)
+}
+
+// Refresh #ueber Default-Manager
+func Refresh(refreshToken string) (*LoginUser, error) {
+	return Default().Refresh(refreshToken)
+}
+
+// GetRefreshToken #ueber Default-Manager
+func GetRefreshToken(accessToken string) (*jwt.JWToken, error) {
+	return Default().GetRefreshToken(accessToken)
+}
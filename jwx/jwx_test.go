@@ -0,0 +1,779 @@
+package jwx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/waldurbas/got/cnv"
+	"github.com/waldurbas/got/jwt"
+	"github.com/waldurbas/got/jwx"
+)
+
+func Test_Store(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "sessions.json")
+
+	fs, err := jwx.NewFileStore(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := jwx.NewManager(fs)
+	cl := map[string]interface{}{"uid": "u1"}
+
+	lu, err := m.Login("user1", &jwx.LoginUser{App: "kiosk", UUID: "u1", SignKey: "geheim", Pwd: "klartext"}, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Ergebnis ist eine Kopie
+	lu.App = "web"
+	if x, _ := fs.Get("user1"); x.App != "kiosk" {
+		t.Errorf("Login: Store geaendert: %s", x.App)
+	}
+	lu.App = "kiosk"
+
+	if b, _ := ioutil.ReadFile(fn); bytes.Contains(b, []byte("klartext")) {
+		t.Errorf("FileStore: Pwd gespeichert")
+	}
+
+	// Neustart
+	fs2, err := jwx.NewFileStore(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m2 := jwx.NewManager(fs2)
+
+	lu2 := m2.CheckUserToken("user1")
+	if lu2 == nil || lu2.Sub != lu.Sub || lu2.App != "kiosk" {
+		t.Fatalf("CheckUserToken nach Neustart: %+v", lu2)
+	}
+
+	if x, err := fs2.GetBySub(lu.Sub); err != nil || x.UUID != "u1" {
+		t.Errorf("GetBySub: %v %v", x, err)
+	}
+
+	rt, err := m2.GetRefreshToken(lu.Access.RawData)
+	if err != nil || rt.RawData != lu.Refresh.RawData {
+		t.Errorf("GetRefreshToken: %v", err)
+	}
+
+	if mt := m2.GetMemToken("kiosk"); len(mt) != 1 {
+		t.Errorf("GetMemToken: %d", len(mt))
+	}
+
+	m2.TokenDelete("u1")
+	if _, err := fs2.Get("user1"); err != jwx.ErrNoSession {
+		t.Errorf("TokenDelete: %v", err)
+	}
+
+	fs3, _ := jwx.NewFileStore(fn)
+	if _, err := fs3.Get("user1"); err != jwx.ErrNoSession {
+		t.Errorf("TokenDelete nicht gespeichert: %v", err)
+	}
+}
+
+func Test_FileStoreKey(t *testing.T) {
+	fn := filepath.Join(t.TempDir(), "sessions.jwe")
+	k, _ := jwt.NewJWK(bytes.Repeat([]byte{3}, 32), "fs", "A256KW")
+
+	fs, err := jwx.NewFileStoreKey(fn, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := jwx.NewManager(fs)
+	lu, _ := m.Login("user1", &jwx.LoginUser{App: "kiosk", SignKey: "geheim"}, nil)
+
+	b, _ := ioutil.ReadFile(fn)
+	if bytes.Contains(b, []byte("geheim")) || bytes.Contains(b, []byte(lu.Sub)) || !jwt.IsEncrypted(string(b)) {
+		t.Errorf("FileStoreKey: Klartext in %s", fn)
+	}
+
+	if fi, err := os.Stat(fn); err != nil || fi.Mode().Perm() != 0600 {
+		t.Errorf("FileStoreKey: Mode %v %v", fi.Mode(), err)
+	}
+
+	fs2, err := jwx.NewFileStoreKey(fn, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x, err := fs2.Get("user1"); err != nil || x.Sub != lu.Sub {
+		t.Errorf("Neustart: %v %v", x, err)
+	}
+
+	k2, _ := jwt.NewJWK(bytes.Repeat([]byte{4}, 32), "fs", "A256KW")
+	if _, err := jwx.NewFileStoreKey(fn, k2); err == nil {
+		t.Error("falscher Schluessel")
+	}
+}
+
+func Test_Sweep(t *testing.T) {
+	s := jwx.NewMemStore()
+	s.Put("a", &jwx.LoginUser{Sub: "1", Expired: 100})
+	s.Put("b", &jwx.LoginUser{Sub: "2", Expired: 300})
+
+	if ll, _ := s.Sweep(200); len(ll) != 1 || ll[0].Sub != "1" {
+		t.Errorf("Sweep: %v", ll)
+	}
+
+	if _, err := s.GetBySub("1"); err != jwx.ErrNoSession {
+		t.Errorf("GetBySub nach Sweep: %v", err)
+	}
+}
+
+func Test_Default(t *testing.T) {
+	old := jwx.SetDefault(jwx.NewManager(nil))
+	defer jwx.SetDefault(old)
+
+	cl := map[string]interface{}{}
+	lu, err := jwx.GenerateTokenPair(&cl, "k", "geheim")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if jwx.CheckUserToken("k") == nil || jwx.RawToken2Sub(lu.Access.RawData) != lu.Sub {
+		t.Error("Default-Manager")
+	}
+
+	jwx.TokenDelete("all")
+	if jwx.CheckUserToken("k") != nil {
+		t.Error("TokenDelete all")
+	}
+}
+
+func Test_GenerateTokenPair(t *testing.T) {
+	old := jwx.SetDefault(jwx.NewManager(nil))
+	defer jwx.SetDefault(old)
+
+	// Aufrufmuster wie bisher: Felder nach GenerateTokenPair setzen
+	cl := map[string]interface{}{"user": "wu"}
+	lu, err := jwx.GenerateTokenPair(&cl, "k", "geheim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lu.App = "web"
+	lu.UUID = "u1"
+	lu.Pwd = "pwd"
+
+	if mt := jwx.GetMemToken("web"); len(mt) != 1 || mt[0].UUID != "u1" {
+		t.Errorf("GetMemToken: %+v", mt)
+	}
+
+	if x := jwx.CheckUserToken("k"); x == nil || x.Pwd != "pwd" || x.App != "web" {
+		t.Errorf("CheckUserToken: %+v", x)
+	}
+
+	jwx.TokenDelete("u1")
+	if jwx.CheckUserToken("k") != nil {
+		t.Error("TokenDelete uid")
+	}
+}
+
+func Test_SetDefault(t *testing.T) {
+	m := jwx.NewManager(nil)
+	old := jwx.SetDefault(m)
+	defer jwx.SetDefault(old)
+
+	lu, _ := m.Login("k", &jwx.LoginUser{SignKey: "geheim"}, nil)
+	h := jwx.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	call := func() int {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("Authorization", "Bearer "+lu.Access.RawData)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			jwx.SetDefault(m)
+		}
+	}()
+
+	for i := 0; i < 100; i++ {
+		if code := call(); code != 200 {
+			t.Fatalf("Middleware: %d", code)
+		}
+	}
+	wg.Wait()
+
+	// bereits erstellter Handler folgt SetDefault
+	jwx.SetDefault(jwx.NewManager(nil))
+	if code := call(); code != 401 {
+		t.Errorf("Middleware nach SetDefault: %d", code)
+	}
+}
+
+func Test_Rotation(t *testing.T) {
+	k, _ := jwt.NewJWK(bytes.Repeat([]byte{7}, 32), "r1", "A256KW")
+
+	m := jwx.NewManager(nil)
+	m.RefreshKey = k
+
+	var ev []*jwx.Event
+	m.AddHook(func(e *jwx.Event) {
+		if e.Type == jwx.EventReuseDetected {
+			ev = append(ev, e)
+		}
+	})
+
+	cl := map[string]interface{}{"role": "admin"}
+	lu, err := m.GenerateTokenPair(&cl, "user1", "geheim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt1 := lu.Refresh.RawData
+
+	lu2, err := m.Refresh(rt1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if lu2.Refresh.RawData == rt1 || lu2.Sub != lu.Sub || lu2.Refresh.Claims["role"] != "admin" {
+		t.Fatalf("Refresh: kein neues Paar")
+	}
+
+	// neuer Token ist gueltig, danach verbraucht
+	rt2 := lu2.Refresh.RawData
+	if _, err := m.Refresh(rt2); err != nil {
+		t.Fatal(err)
+	}
+
+	// alter Token nochmal: Session wird widerrufen
+	if _, err := m.Refresh(rt1); err != jwx.ErrRefreshReused {
+		t.Fatalf("Reuse: %v", err)
+	}
+
+	if m.CheckUserToken("user1") != nil {
+		t.Error("Session nach Reuse noch vorhanden")
+	}
+
+	if len(ev) != 1 || ev[0].Type != jwx.EventReuseDetected || ev[0].Key != "user1" {
+		t.Errorf("Event: %+v", ev)
+	}
+
+	if _, err := m.Refresh("x.y.z"); err == nil {
+		t.Error("Refresh mit ungueltigem Token")
+	}
+}
+
+// slowStore #Put verzoegert, damit Refresh und Revoke sich ueberschneiden
+type slowStore struct {
+	*jwx.MemStore
+}
+
+func (s slowStore) Put(key string, lu *jwx.LoginUser) error {
+	time.Sleep(time.Millisecond)
+	return s.MemStore.Put(key, lu)
+}
+
+func Test_RefreshRevoke(t *testing.T) {
+	m := jwx.NewManager(slowStore{jwx.NewMemStore()})
+
+	for i := 0; i < 20; i++ {
+		lu, _ := m.Login("k", &jwx.LoginUser{SignKey: "geheim"}, nil)
+
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func(rt string) {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				x, err := m.Refresh(rt)
+				if err != nil {
+					return
+				}
+				rt = x.Refresh.RawData
+			}
+		}(lu.Refresh.RawData)
+
+		time.Sleep(2 * time.Millisecond)
+		m.Revoke("k")
+		close(stop)
+		wg.Wait()
+
+		if _, err := m.Store.Get("k"); err != jwx.ErrNoSession {
+			t.Fatalf("%d: Session nach Revoke wieder da", i)
+		}
+	}
+}
+
+func Test_GetRefreshToken(t *testing.T) {
+	m := jwx.NewManager(nil)
+	cl := map[string]interface{}{}
+	lu, err := m.GenerateTokenPair(&cl, "user1", "geheim")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sub ist erratbar: Token mit falscher Signatur
+	x := jwt.New()
+	x.Claims["sub"] = lu.Sub
+	x.SetExpiry(time.Minute)
+	x.Encode("falsch")
+	if _, err := m.GetRefreshToken(x.RawData); err == nil {
+		t.Fatal("GetRefreshToken mit falscher Signatur")
+	}
+
+	// Refresh-Token statt Access-Token
+	if _, err := m.GetRefreshToken(lu.Refresh.RawData); err == nil {
+		t.Error("GetRefreshToken mit Refresh-Token")
+	}
+
+	if rt, err := m.GetRefreshToken(lu.Access.RawData); err != nil || rt.RawData != lu.Refresh.RawData {
+		t.Errorf("GetRefreshToken: %v", err)
+	}
+}
+
+func Test_CheckUserToken(t *testing.T) {
+	m := jwx.NewManager(nil)
+	cl := map[string]interface{}{}
+	lu, _ := m.GenerateTokenPair(&cl, "user1", "geheim")
+
+	// Access-Token abgelaufen
+	x, _ := m.Store.Get("user1")
+	x.Access = jwt.New()
+	x.Access.Claims["sub"] = lu.Sub
+	x.Access.Claims["exp"] = time.Now().Unix() - 10
+	x.Access.Encode("geheim")
+	m.Store.Put("user1", x)
+
+	lu2 := m.CheckUserToken("user1")
+	if lu2 == nil || lu2.Access.RawData == x.Access.RawData || lu2.Refresh.RawData != lu.Refresh.RawData {
+		t.Fatalf("CheckUserToken: %+v", lu2)
+	}
+
+	// Refresh-Token des Clients bleibt gueltig
+	if _, err := m.Refresh(lu.Refresh.RawData); err != nil {
+		t.Errorf("Refresh nach CheckUserToken: %v", err)
+	}
+}
+
+func Test_Handlers(t *testing.T) {
+	m := jwx.NewManager(nil)
+	h := &jwx.Handlers{
+		M:       m,
+		SignKey: "geheim",
+		Check: func(r *http.Request, c *jwx.Credentials) (*jwx.Identity, error) {
+			if c.Pwd != "pwd" {
+				return nil, jwx.ErrInvalidCredentials
+			}
+			return &jwx.Identity{UUID: c.User, Claims: map[string]interface{}{"role": "user"}}, nil
+		},
+		Admin: func(r *http.Request) bool { return r.Header.Get("X-Admin") == "1" },
+	}
+
+	mux := http.NewServeMux()
+	h.Register(mux, "/auth")
+
+	call := func(method, url, body, token string, hdr ...string) (int, map[string]interface{}) {
+		r := httptest.NewRequest(method, url, strings.NewReader(body))
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		for i := 0; i+1 < len(hdr); i += 2 {
+			r.Header.Set(hdr[i], hdr[i+1])
+		}
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		var v map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &v)
+		return w.Code, v
+	}
+
+	if code, v := call("POST", "/auth/login", `{"user":"u1","pwd":"x","app":"web"}`, ""); code != 401 || v["message"] == nil {
+		t.Errorf("login falsches Passwort: %d %v", code, v)
+	}
+
+	if code, _ := call("GET", "/auth/login", "", ""); code != 405 {
+		t.Errorf("login GET: %d", code)
+	}
+
+	code, v := call("POST", "/auth/login", `{"user":"u1","pwd":"pwd","app":"web"}`, "")
+	if code != 200 || v["access_token"] == nil {
+		t.Fatalf("login: %d %v", code, v)
+	}
+	rt := v["refresh_token"].(string)
+
+	code, v = call("POST", "/auth/refresh", `{"refresh_token":"`+rt+`"}`, "")
+	if code != 200 || v["refresh_token"] == rt {
+		t.Fatalf("refresh: %d %v", code, v)
+	}
+
+	if code, v := call("POST", "/auth/refresh", `{"refresh_token":"`+rt+`"}`, ""); code != 401 || v["message"] != jwx.ErrRefreshReused.Error() {
+		t.Errorf("refresh reuse: %d %v", code, v)
+	}
+
+	call("POST", "/auth/login", `{"user":"u2","pwd":"pwd","app":"kiosk"}`, "")
+
+	if code, _ := call("GET", "/auth/sessions", "", ""); code != 403 {
+		t.Errorf("sessions ohne Admin: %d", code)
+	}
+
+	r := httptest.NewRequest("GET", "/auth/sessions?app=kiosk", nil)
+	r.Header.Set("X-Admin", "1")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	var ss []jwx.SessionInfo
+	if json.Unmarshal(w.Body.Bytes(), &ss); len(ss) != 1 || ss[0].UUID != "u2" {
+		t.Errorf("sessions: %s", w.Body.String())
+	}
+
+	_, v = call("POST", "/auth/login", `{"user":"u3","pwd":"pwd"}`, "")
+	at := v["access_token"].(string)
+	if code, _ := call("POST", "/auth/logout", "", at); code != 200 {
+		t.Errorf("logout: %d", code)
+	}
+	if code, _ := call("POST", "/auth/logout", "", at); code != 401 {
+		t.Errorf("logout 2x: %d", code)
+	}
+}
+
+func Test_Middleware(t *testing.T) {
+	m := jwx.NewManager(nil)
+
+	cl := map[string]interface{}{"roles": []string{"user"}, "scope": "read write"}
+	lu, _ := m.Login("k1", &jwx.LoginUser{App: "web", UUID: "u1", SignKey: "geheim"}, cl)
+
+	var got *jwx.Principal
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = jwx.PrincipalFrom(r) })
+
+	mux := http.NewServeMux()
+	mux.Handle("/api", m.Middleware(jwx.RequireScope("read")(ok)))
+	mux.Handle("/admin", m.Middleware(jwx.RequireRole("admin")(ok)))
+
+	call := func(url, token string) int {
+		r := httptest.NewRequest("GET", url, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	if code := call("/api", lu.Access.RawData); code != 200 || got == nil || got.UUID != "u1" || !got.HasRole("user") {
+		t.Fatalf("api: %d %+v", code, got)
+	}
+
+	if code := call("/admin", lu.Access.RawData); code != 403 {
+		t.Errorf("admin: %d", code)
+	}
+
+	if code := call("/api", ""); code != 401 {
+		t.Errorf("ohne Token: %d", code)
+	}
+
+	if code := call("/api", lu.Refresh.RawData); code != 401 {
+		t.Errorf("Refresh-Token als Access-Token: %d", code)
+	}
+
+	m.Revoke("k1")
+	if code := call("/api", lu.Access.RawData); code != 401 {
+		t.Errorf("nach Revoke: %d", code)
+	}
+}
+
+func Test_Policy(t *testing.T) {
+	m := jwx.NewManager(nil)
+	m.SetPolicy("kiosk", &jwx.Policy{AccessTTL: 8 * time.Hour})
+	m.SetPolicy("admin", &jwx.Policy{AccessTTL: 5 * time.Minute, MaxSessions: 2, IdleTimeout: time.Hour, MaxLifetime: 2 * time.Hour})
+
+	login := func(key, app string) *jwx.LoginUser {
+		lu, err := m.Login(key, &jwx.LoginUser{App: app, UUID: "u1", SignKey: "geheim"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return lu
+	}
+
+	ttl := func(lu *jwx.LoginUser) int64 {
+		return lu.Access.Claims.AsInt64("exp") - lu.Access.Claims.AsInt64("iat")
+	}
+
+	if d := ttl(login("k", "kiosk")); d != 8*3600 {
+		t.Errorf("kiosk AccessTTL: %d", d)
+	}
+
+	a1 := login("a1", "admin")
+	if d := ttl(a1); d != 300 {
+		t.Errorf("admin AccessTTL: %d", d)
+	}
+
+	// MaxLifetime begrenzt den Refresh-Token
+	if d := a1.Expired - a1.Created; d != 2*3600 {
+		t.Errorf("MaxLifetime: %d", d)
+	}
+
+	// MaxSessions: a1 wird beim dritten Login entfernt
+	login("a2", "admin")
+	login("a3", "admin")
+	if m.CheckUserToken("a1") != nil || m.CheckUserToken("a2") == nil || m.CheckUserToken("k") == nil {
+		t.Error("MaxSessions")
+	}
+
+	// IdleTimeout
+	lu, _ := m.Store.Get("a3")
+	lu.LastSeen -= 2 * 3600
+	m.Store.Put("a3", lu)
+
+	if p, msg := m.Authenticate(lu.Access.RawData); p != nil || msg != "session idle" {
+		t.Errorf("IdleTimeout Authenticate: %v %s", p, msg)
+	}
+	if m.CheckUserToken("a3") != nil {
+		t.Error("IdleTimeout CheckUserToken")
+	}
+
+	// absolute Obergrenze
+	lu, _ = m.Store.Get("a2")
+	lu.Created -= 3 * 3600
+	m.Store.Put("a2", lu)
+
+	if n, _ := m.Sweep(); n != 1 {
+		t.Errorf("Sweep MaxLifetime: %d", n)
+	}
+
+	// GenerateTokenPair: App und UUID aus den Claims
+	m.SetPolicy("kiosk", &jwx.Policy{AccessTTL: 8 * time.Hour, MaxSessions: 1})
+	cl := map[string]interface{}{"app": "kiosk", "uuid": "u9"}
+	g1, err := m.GenerateTokenPair(&cl, "g1", "geheim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := ttl(g1); d != 8*3600 || g1.App != "kiosk" || g1.UUID != "u9" {
+		t.Errorf("GenerateTokenPair Policy: %d %s %s", d, g1.App, g1.UUID)
+	}
+
+	m.GenerateTokenPair(&cl, "g2", "geheim")
+	if m.CheckUserToken("g1") != nil || m.CheckUserToken("g2") == nil {
+		t.Error("GenerateTokenPair MaxSessions")
+	}
+}
+
+func Test_Janitor(t *testing.T) {
+	m := jwx.NewManager(nil)
+
+	var mu sync.Mutex
+	ev := map[jwx.EventType]int{}
+	m.AddHook(func(e *jwx.Event) {
+		mu.Lock()
+		ev[e.Type]++
+		mu.Unlock()
+	})
+
+	lu, _ := m.Login("k1", &jwx.LoginUser{UUID: "u1", SignKey: "geheim"}, nil)
+	m.Refresh(lu.Refresh.RawData)
+	m.Login("k2", &jwx.LoginUser{UUID: "u2", SignKey: "geheim"}, nil)
+	m.TokenDelete("u2")
+
+	// k1 abgelaufen
+	lu, _ = m.Store.Get("k1")
+	lu.Expired = time.Now().Unix() - 1
+	m.Store.Put("k1", lu)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	m.StartJanitor(ctx, 10*time.Millisecond)
+
+	for i := 0; i < 100; i++ {
+		if _, err := m.Store.Get("k1"); err == jwx.ErrNoSession {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := map[jwx.EventType]int{jwx.EventCreated: 2, jwx.EventRefreshed: 1, jwx.EventRevoked: 1, jwx.EventExpired: 1}
+	for k, v := range want {
+		if ev[k] != v {
+			t.Errorf("Event %s: %d, soll %d", k, ev[k], v)
+		}
+	}
+}
+
+func Test_HookReentry(t *testing.T) {
+	m := jwx.NewManager(nil)
+
+	// Hook benutzt den Manager: kein Deadlock
+	var n int
+	m.AddHook(func(e *jwx.Event) {
+		if e.Type == jwx.EventRefreshed || e.Type == jwx.EventReuseDetected {
+			m.CheckUserToken(e.Key)
+			n++
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cl := map[string]interface{}{}
+		lu, _ := m.GenerateTokenPair(&cl, "k", "geheim")
+		m.Refresh(lu.Refresh.RawData)
+		m.Refresh(lu.Refresh.RawData)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Hook: Deadlock")
+	}
+
+	if n != 2 {
+		t.Errorf("Hook: %d Events", n)
+	}
+}
+
+func Test_Password(t *testing.T) {
+	for _, alg := range []string{"argon2id", "scrypt", "pbkdf2-sha256"} {
+		h, err := jwx.HashPasswordWith(alg, "geheim")
+		if err != nil || !strings.HasPrefix(h, "$"+alg+"$") {
+			t.Fatalf("%s: %s %v", alg, h, err)
+		}
+
+		ok, upgrade, err := jwx.VerifyPassword(h, "geheim")
+		if !ok || err != nil || upgrade != (alg != jwx.DefaultHash) {
+			t.Errorf("%s: ok=%v upgrade=%v %v", alg, ok, upgrade, err)
+		}
+
+		if ok, _, _ := jwx.VerifyPassword(h, "falsch"); ok {
+			t.Errorf("%s: falsches Passwort akzeptiert", alg)
+		}
+	}
+
+	// schwaechere Parameter
+	old := jwx.Argon2Time
+	jwx.Argon2Time = 1
+	h, _ := jwx.HashPassword("geheim")
+	jwx.Argon2Time = old
+	if ok, upgrade, _ := jwx.VerifyPassword(h, "geheim"); !ok || !upgrade {
+		t.Errorf("argon2id t=1: ok=%v upgrade=%v", ok, upgrade)
+	}
+
+	b := []byte("geheim")
+	md5 := cnv.Md5HexString(&b)
+
+	for _, stored := range []string{"geheim", md5} {
+		var saved string
+		ok, err := jwx.CheckPassword(stored, "geheim", func(h string) error { saved = h; return nil })
+		if !ok || err != nil || !strings.HasPrefix(saved, "$argon2id$") {
+			t.Errorf("Legacy %s: ok=%v saved=%s %v", stored, ok, saved, err)
+		}
+
+		if ok, _ := jwx.CheckPassword(stored, "falsch", nil); ok {
+			t.Errorf("Legacy %s: falsches Passwort akzeptiert", stored)
+		}
+	}
+
+	if _, _, err := jwx.VerifyPassword("$argon2id$v=19$xx$yy", "geheim"); err != jwx.ErrHashFormat {
+		t.Errorf("ErrHashFormat: %v", err)
+	}
+}
+
+func Test_Throttle(t *testing.T) {
+	th := jwx.NewThrottle(2, 3, 100*time.Millisecond)
+
+	th.Allow("u1", "ip1")
+	th.Allow("u1", "ip1")
+	if d, ok := th.Allow("u1", "ip2"); ok || d <= 0 {
+		t.Errorf("User gesperrt: %v %v", d, ok)
+	}
+
+	th.Allow("u2", "ip1")
+	if _, ok := th.Allow("u3", "ip1"); ok {
+		t.Error("IP gesperrt")
+	}
+
+	if _, ok := th.Allow("u3", "ip2"); !ok {
+		t.Error("u3/ip2 frei")
+	}
+
+	time.Sleep(110 * time.Millisecond)
+	if _, ok := th.Allow("u1", "ip1"); !ok {
+		t.Error("nach Window frei")
+	}
+
+	// Success nimmt den Versuch zurueck
+	for i := 0; i < 5; i++ {
+		if _, ok := th.Allow("u5", "ip5"); !ok {
+			t.Fatalf("Success: %d gesperrt", i)
+		}
+		th.Success("u5", "ip5")
+	}
+
+	// parallele Versuche: genau MaxUser
+	tp := jwx.NewThrottle(2, 0, time.Minute)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	n := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := tp.Allow("u1", "ip1"); ok {
+				mu.Lock()
+				n++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if n != 2 {
+		t.Errorf("parallel: %d Versuche erlaubt", n)
+	}
+
+	// ohne NewThrottle
+	tl := &jwx.Throttle{MaxUser: 1, Window: time.Minute}
+	tl.Success("u1", "ip1")
+	if _, ok := tl.Allow("u1", "ip1"); !ok {
+		t.Error("Literal: erster Versuch")
+	}
+	if _, ok := tl.Allow("u1", "ip1"); ok {
+		t.Error("Literal: MaxUser")
+	}
+
+	// Handler: 429 nach zu vielen Fehlversuchen
+	h := &jwx.Handlers{
+		M:        jwx.NewManager(nil),
+		SignKey:  "geheim",
+		Throttle: jwx.NewThrottle(2, 0, time.Minute),
+		Check: func(r *http.Request, c *jwx.Credentials) (*jwx.Identity, error) {
+			return nil, jwx.ErrInvalidCredentials
+		},
+	}
+
+	codes := []int{}
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		h.Login().ServeHTTP(w, httptest.NewRequest("POST", "/login", strings.NewReader(`{"user":"u1","pwd":"x"}`)))
+		codes = append(codes, w.Code)
+	}
+
+	if codes[0] != 401 || codes[1] != 401 || codes[2] != 429 {
+		t.Errorf("Login Throttle: %v", codes)
+	}
+}
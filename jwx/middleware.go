@@ -0,0 +1,221 @@
+package jwx
+
+// ----------------------------------------------------------------------------------
+// middleware.go (https://github.com/waldurbas/got): bearer authentication middleware
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Middleware: Default-Manager je Request, ohne neuen Handler
+// 2026.10.19 (wu) Policy: IdleTimeout, MaxLifetime
+// 2026.10.19 (wu) Init: Principal im Context, RequireRole, RequireScope
+//-----------------------------------------------------------------------------------
+//
+//	mux.Handle("/api/", jwx.Middleware(api))
+//	mux.Handle("/admin/", jwx.Middleware(jwx.RequireRole("admin")(admin)))
+//
+//	func api(w http.ResponseWriter, r *http.Request) {
+//		p := jwx.PrincipalFrom(r)
+//		...
+//	}
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/waldurbas/got/htx"
+	"github.com/waldurbas/got/jwt"
+)
+
+// Principal #angemeldeter Benutzer im Request-Context
+type Principal struct {
+	Key    string
+	Sub    string
+	UUID   string
+	App    string
+	URL    string
+	Roles  []string // Claim "role" oder "roles" der Session
+	Scopes []string // Claim "scope" (mit Leerzeichen getrennt) oder "scp"
+	Claims jwt.TMap // Claims der Session (aus dem Refresh-Token)
+}
+
+type ctxKey int
+
+const principalKey ctxKey = 0
+
+// HasRole #
+func (p *Principal) HasRole(role string) bool {
+	return contains(p.Roles, role)
+}
+
+// HasScope #
+func (p *Principal) HasScope(scope string) bool {
+	return contains(p.Scopes, scope)
+}
+
+// PrincipalFrom #nil ohne Middleware
+func PrincipalFrom(r *http.Request) *Principal {
+	return FromContext(r.Context())
+}
+
+// FromContext #
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(principalKey).(*Principal)
+	return p
+}
+
+// WithPrincipal #z.B. fuer Tests
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey, p)
+}
+
+// Middleware #ueber Default-Manager, SetDefault gilt auch fuer bereits erstellte Handler
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Default().serve(next, w, r)
+	})
+}
+
+// Middleware #Bearer-Token pruefen (Signatur mit SignKey der Session), Session ueber sub,
+// sonst 401 mit JSON-Body
+func (m *Manager) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.serve(next, w, r)
+	})
+}
+
+func (m *Manager) serve(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	p, msg := m.Authenticate(BearerToken(r))
+	if p == nil {
+		unauthorized(w, msg)
+		return
+	}
+
+	next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+}
+
+// Authenticate #Access-Token pruefen, bei Fehler nil und Grund
+func (m *Manager) Authenticate(raw string) (*Principal, string) {
+	if raw == "" {
+		return nil, "missing token"
+	}
+
+	t := jwt.New()
+	if t.Parse(raw) != nil {
+		return nil, "invalid token"
+	}
+
+	// Refresh-Token haben eine jti und sind kein Access-Token
+	if _, ok := t.Claims["jti"]; ok {
+		return nil, "invalid token"
+	}
+
+	lu, err := m.Store.GetBySub(t.Claims.AsString("sub"))
+	if err != nil {
+		return nil, "session revoked"
+	}
+
+	switch err := t.Valid(lu.SignKey); err {
+	case nil:
+	case jwt.ErrTokenExpired:
+		return nil, "token expired"
+	default:
+		return nil, "invalid token"
+	}
+
+	p := m.Policy(lu.App)
+	now := time.Now().Unix()
+	if msg := alive(p, lu, now); msg != "" {
+		return nil, msg
+	}
+
+	m.touch(p, lu, now)
+	return newPrincipal(lu), ""
+}
+
+func newPrincipal(lu *LoginUser) *Principal {
+	p := &Principal{Key: lu.Key, Sub: lu.Sub, UUID: lu.UUID, App: lu.App, URL: lu.URL, Claims: jwt.TMap{}}
+
+	if lu.Refresh != nil {
+		for k, v := range lu.Refresh.Claims {
+			p.Claims[k] = v
+		}
+	}
+
+	p.Roles = append(p.Claims.AsStrings("role"), p.Claims.AsStrings("roles")...)
+	p.Scopes = p.Claims.AsStrings("scp")
+	if s := p.Claims.AsString("scope"); s != "" {
+		p.Scopes = append(p.Scopes, strings.Fields(s)...)
+	}
+
+	return p
+}
+
+// RequireRole #403 wenn der Principal keine der Rollen hat, 401 ohne Principal
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return require(func(p *Principal) bool {
+		for _, s := range roles {
+			if p.HasRole(s) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// RequireScope #403 wenn der Principal nicht alle Scopes hat, 401 ohne Principal
+func RequireScope(scopes ...string) func(http.Handler) http.Handler {
+	return require(func(p *Principal) bool {
+		for _, s := range scopes {
+			if !p.HasScope(s) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func require(ok func(p *Principal) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			p := PrincipalFrom(r)
+			if p == nil {
+				unauthorized(w, "missing token")
+				return
+			}
+
+			if !ok(p) {
+				htx.WriteResponseMsg("jwx.auth", w, http.StatusForbidden, "forbidden")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, msg string) {
+	if msg == "missing token" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	} else {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+	htx.WriteResponseMsg("jwx.auth", w, http.StatusUnauthorized, msg)
+}
+
+func contains(ss []string, s string) bool {
+	for _, x := range ss {
+		if x == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,200 @@
+package jwx
+
+// ----------------------------------------------------------------------------------
+// policy.go (https://github.com/waldurbas/got): token lifetimes and session limits per App
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) limitSessions unter m.mu
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+//
+//	jwx.SetPolicy("kiosk", &jwx.Policy{AccessTTL: 8 * time.Hour})
+//	jwx.SetPolicy("admin", &jwx.Policy{AccessTTL: 5 * time.Minute, IdleTimeout: 30 * time.Minute, MaxSessions: 1})
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Policy #je LoginUser.App, leere Felder: Manager bzw. Paket-Variablen
+type Policy struct {
+	AccessTTL   time.Duration
+	RefreshTTL  time.Duration
+	MaxSessions int           // je UUID und App, aelteste Sessions werden beim Login entfernt; 0: unbegrenzt
+	IdleTimeout time.Duration // ohne Aktivitaet (CheckUserToken, Refresh, Middleware); 0: ohne
+	MaxLifetime time.Duration // absolute Obergrenze ab Login, auch mit Refresh; 0: ohne
+}
+
+var noPolicy = &Policy{}
+
+// SetPolicy #app "": fuer alle Apps ohne eigene Policy, p == nil: loeschen
+func (m *Manager) SetPolicy(app string, p *Policy) {
+	m.pmu.Lock()
+	defer m.pmu.Unlock()
+
+	if m.policies == nil {
+		m.policies = make(map[string]*Policy)
+	}
+
+	if p == nil {
+		delete(m.policies, app)
+	} else {
+		m.policies[app] = p
+	}
+}
+
+// Policy #Policy der App, nie nil
+func (m *Manager) Policy(app string) *Policy {
+	m.pmu.RLock()
+	defer m.pmu.RUnlock()
+
+	if p, ok := m.policies[app]; ok {
+		return p
+	}
+	if p, ok := m.policies[""]; ok {
+		return p
+	}
+
+	return noPolicy
+}
+
+// SetPolicy #ueber Default-Manager
+func SetPolicy(app string, p *Policy) {
+	Default().SetPolicy(app, p)
+}
+
+func (m *Manager) accessTTL(p *Policy) time.Duration {
+	if p.AccessTTL > 0 {
+		return p.AccessTTL
+	}
+	if m.AccessTTL > 0 {
+		return m.AccessTTL
+	}
+
+	return DurationAccessToken
+}
+
+func (m *Manager) refreshTTL(p *Policy) time.Duration {
+	if p.RefreshTTL > 0 {
+		return p.RefreshTTL
+	}
+	if m.RefreshTTL > 0 {
+		return m.RefreshTTL
+	}
+
+	return DurationRefreshToken
+}
+
+// capExp #exp nicht ueber MaxLifetime hinaus
+func capExp(p *Policy, lu *LoginUser, exp int64) int64 {
+	if p.MaxLifetime > 0 && lu.Created > 0 {
+		if lim := lu.Created + int64(p.MaxLifetime/time.Second); exp > lim {
+			return lim
+		}
+	}
+
+	return exp
+}
+
+// alive #"" oder Grund, warum die Session beendet ist
+func alive(p *Policy, lu *LoginUser, now int64) string {
+	if lu.Expired <= now {
+		return "session expired"
+	}
+
+	if p.MaxLifetime > 0 && lu.Created > 0 && lu.Created+int64(p.MaxLifetime/time.Second) <= now {
+		return "session expired"
+	}
+
+	if p.IdleTimeout > 0 {
+		last := lu.LastSeen
+		if last == 0 {
+			last = lu.Created
+		}
+		if last > 0 && last+int64(p.IdleTimeout/time.Second) <= now {
+			return "session idle"
+		}
+	}
+
+	return ""
+}
+
+// touch #LastSeen nur mit IdleTimeout und hoechstens alle IdleTimeout/10 speichern
+func (m *Manager) touch(p *Policy, lu *LoginUser, now int64) {
+	if p.IdleTimeout <= 0 {
+		return
+	}
+
+	step := int64(p.IdleTimeout / time.Second / 10)
+	if step < 1 {
+		step = 1
+	}
+
+	if now-lu.LastSeen < step {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if cur, err := m.Store.Get(lu.Key); err == nil {
+		cur.LastSeen = now
+		m.Store.Put(cur.Key, cur)
+	}
+}
+
+// limitSessions #aelteste Sessions der UUID/App entfernen, damit max-1 bleiben
+func (m *Manager) limitSessions(p *Policy, lu *LoginUser) {
+	if p.MaxSessions <= 0 || lu.UUID == "" {
+		return
+	}
+
+	var ev events
+	defer func() { m.fire(ev...) }()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var ll []*LoginUser
+	m.Store.Range(func(k string, v *LoginUser) bool {
+		if v.UUID == lu.UUID && v.App == lu.App {
+			ll = append(ll, v)
+		}
+		return true
+	})
+
+	if len(ll) < p.MaxSessions {
+		return
+	}
+
+	sort.Slice(ll, func(i, j int) bool {
+		if ll[i].Created != ll[j].Created {
+			return ll[i].Created < ll[j].Created
+		}
+		return subSeq(ll[i].Sub) < subSeq(ll[j].Sub)
+	})
+
+	for _, v := range ll[:len(ll)-p.MaxSessions+1] {
+		m.remove(v.Key, "max sessions", &ev)
+	}
+}
+
+// subSeq #laufende Nummer aus sub ("rest.prefix", siehe Login)
+func subSeq(sub string) int64 {
+	ss := strings.SplitN(sub, ".", 2)
+	if len(ss) != 2 {
+		return 0
+	}
+
+	n, _ := strconv.ParseInt(ss[1]+ss[0], 10, 64)
+	return n
+}
@@ -0,0 +1,298 @@
+package jwx
+
+// ----------------------------------------------------------------------------------
+// store.go (https://github.com/waldurbas/got): session store for jwx
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) FileStore: Datei 0600, NewFileStoreKey verschluesselt (JWE)
+// 2026.10.19 (wu) MemStore.share fuer GenerateTokenPair
+// 2026.10.19 (wu) Put speichert eine Kopie, FileStore ohne Pwd
+// 2026.10.19 (wu) Sweep liefert die geloeschten Sessions
+// 2026.10.19 (wu) Init: MemStore, FileStore
+//-----------------------------------------------------------------------------------
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/waldurbas/got/jwt"
+)
+
+// ErrNoSession #Session nicht im Store
+var ErrNoSession = errors.New("session not found")
+
+// Store #Sessions nach key (Login) und sub (aus dem Token)
+// Put speichert eine Kopie von lu, Get und GetBySub liefern eine Kopie
+type Store interface {
+	Put(key string, lu *LoginUser) error
+	Get(key string) (*LoginUser, error)
+	GetBySub(sub string) (*LoginUser, error)
+	Delete(key string) error
+	// Sweep #loescht Sessions mit Expired <= now, liefert die geloeschten
+	Sweep(now int64) ([]*LoginUser, error)
+	// Range #fn mit Kopien, Abbruch bei false
+	Range(fn func(key string, lu *LoginUser) bool) error
+}
+
+//------------- MemStore ------------------------
+
+// MemStore #Default, Sessions nur im Speicher
+type MemStore struct {
+	mu    sync.RWMutex
+	token map[string]*LoginUser // key: user
+	sub   map[string]string     // key: sub, value: user
+}
+
+// NewMemStore #
+func NewMemStore() *MemStore {
+	return &MemStore{
+		token: make(map[string]*LoginUser, 100),
+		sub:   make(map[string]string, 100),
+	}
+}
+
+// Put #
+func (s *MemStore) Put(key string, lu *LoginUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(key, lu)
+	return nil
+}
+
+func (s *MemStore) put(key string, lu *LoginUser) {
+	if old, ok := s.token[key]; ok && old.Sub != lu.Sub {
+		delete(s.sub, old.Sub)
+	}
+
+	lu.Key = key
+	s.token[key] = lu.clone()
+	s.sub[lu.Sub] = key
+}
+
+// share #lu selbst statt einer Kopie unter key, nur wenn es noch dieselbe Session ist
+// (GenerateTokenPair: Aenderungen des Aufrufers wirken wie bisher im Store)
+func (s *MemStore) share(key string, lu *LoginUser) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if old, ok := s.token[key]; ok && old.Sub == lu.Sub {
+		s.token[key] = lu
+	}
+}
+
+// Get #
+func (s *MemStore) Get(key string) (*LoginUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if lu, ok := s.token[key]; ok {
+		return lu.clone(), nil
+	}
+
+	return nil, ErrNoSession
+}
+
+// GetBySub #
+func (s *MemStore) GetBySub(sub string) (*LoginUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if key, ok := s.sub[sub]; ok {
+		if lu, ok := s.token[key]; ok {
+			return lu.clone(), nil
+		}
+	}
+
+	return nil, ErrNoSession
+}
+
+// Delete #
+func (s *MemStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delete(key)
+	return nil
+}
+
+func (s *MemStore) delete(key string) bool {
+	lu, ok := s.token[key]
+	if !ok {
+		return false
+	}
+
+	delete(s.sub, lu.Sub)
+	delete(s.token, key)
+	return true
+}
+
+// Sweep #
+func (s *MemStore) Sweep(now int64) ([]*LoginUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sweep(now), nil
+}
+
+func (s *MemStore) sweep(now int64) []*LoginUser {
+	var ll []*LoginUser
+	for k, v := range s.token {
+		if (v.Expired - now) < 1 {
+			s.delete(k)
+			ll = append(ll, v)
+		}
+	}
+
+	return ll
+}
+
+// Range #
+func (s *MemStore) Range(fn func(key string, lu *LoginUser) bool) error {
+	s.mu.RLock()
+	ll := make(map[string]*LoginUser, len(s.token))
+	for k, v := range s.token {
+		ll[k] = v.clone()
+	}
+	s.mu.RUnlock()
+
+	for k, v := range ll {
+		if !fn(k, v) {
+			break
+		}
+	}
+
+	return nil
+}
+
+//------------- FileStore ------------------------
+
+// FileStore #MemStore, der nach jeder Aenderung als JSON-Datei gespeichert wird
+// und beim Start wieder geladen wird; Sessions ueberleben einen Neustart.
+// LoginUser.Pwd wird nicht gespeichert, SignKey und die Token aber schon:
+// wer die Datei lesen kann, kann Token faelschen und Sessions uebernehmen.
+// Die Datei wird daher mit 0600 angelegt, mit NewFileStoreKey verschluesselt (JWE)
+type FileStore struct {
+	MemStore
+	fn  string
+	key *jwt.JWK
+}
+
+// NewFileStore #laedt fn, wenn vorhanden; Datei im Klartext (JSON)
+func NewFileStore(fn string) (*FileStore, error) {
+	return NewFileStoreKey(fn, nil)
+}
+
+// NewFileStoreKey #wie NewFileStore, Datei mit k (oct, 32 Bytes) als JWE verschluesselt
+func NewFileStoreKey(fn string, k *jwt.JWK) (*FileStore, error) {
+	s := &FileStore{fn: fn, key: k}
+	s.token = make(map[string]*LoginUser, 100)
+	s.sub = make(map[string]string, 100)
+
+	b, err := ioutil.ReadFile(fn)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if k != nil && len(b) > 0 {
+		if b, _, err = k.Decrypt(string(b)); err != nil {
+			return nil, err
+		}
+	}
+
+	m := map[string]*LoginUser{}
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &m); err != nil {
+			return nil, err
+		}
+	}
+
+	for k, v := range m {
+		s.put(k, v)
+	}
+
+	return s, nil
+}
+
+// Put #
+func (s *FileStore) Put(key string, lu *LoginUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.put(key, lu)
+	return s.save()
+}
+
+// Delete #
+func (s *FileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.delete(key) {
+		return nil
+	}
+
+	return s.save()
+}
+
+// Sweep #
+func (s *FileStore) Sweep(now int64) ([]*LoginUser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ll := s.sweep(now)
+	if len(ll) == 0 {
+		return nil, nil
+	}
+
+	return ll, s.save()
+}
+
+// save #ueber temp. Datei (0600) und Rename, damit die Datei nie halb geschrieben ist
+func (s *FileStore) save() error {
+	b, err := json.Marshal(s.token)
+	if err != nil {
+		return err
+	}
+
+	if s.key != nil {
+		x, err := jwt.Encrypt(b, "", s.key, nil)
+		if err != nil {
+			return err
+		}
+		b = []byte(x)
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(s.fn), filepath.Base(s.fn)+".*")
+	if err != nil {
+		return err
+	}
+
+	if err = f.Chmod(0600); err == nil {
+		_, err = f.Write(b)
+	}
+	if e := f.Close(); err == nil {
+		err = e
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return os.Rename(f.Name(), s.fn)
+}
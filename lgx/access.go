@@ -0,0 +1,267 @@
+package lgx
+
+// ----------------------------------------------------------------------------------
+// access.go (https://github.com/waldurbas/got): http access-log middleware
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) RemoteIP: X-Forwarded-For/X-Real-Ip nur von TrustedProxies
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AccessCommon    #NCSA Common Log Format
+// AccessCombined  #Common + Referer und User-Agent
+// AccessKV        #key=value Felder
+// AccessJSON      #eine JSON-Zeile je Request
+const (
+	AccessCommon = iota
+	AccessCombined
+	AccessKV
+	AccessJSON
+)
+
+// RequestIDHeader #
+const RequestIDHeader = "X-Request-Id"
+
+// TrustedProxies #IP oder CIDR (z.B. "10.0.0.0/8"), nur von diesen werden
+// X-Forwarded-For und X-Real-Ip uebernommen; leer: Header werden ignoriert
+var TrustedProxies []string
+
+type ctxKey int
+
+const reqIDKey ctxKey = 0
+
+// AccessOpt #
+type AccessOpt struct {
+	Format  int      // AccessCommon, AccessCombined, AccessKV, AccessJSON
+	Log     *Lgx     // nil: Standard-Logger
+	Sample  int      // nur jeden n-ten Request loggen (0,1: alle), Status >= 400 immer
+	Exclude []string // Pfade ohne Log, z.B. "/health"; "/static/*" als Prefix
+}
+
+// AccessLog #http.Handler mit Access-Log
+func AccessLog(next http.Handler, opt *AccessOpt) http.Handler {
+	if opt == nil {
+		opt = &AccessOpt{}
+	}
+
+	var cnt uint64
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rid := r.Header.Get(RequestIDHeader)
+		if rid == "" {
+			rid = uuid.New().String()
+		}
+		w.Header().Set(RequestIDHeader, rid)
+		r = r.WithContext(context.WithValue(r.Context(), reqIDKey, rid))
+
+		if opt.excluded(r.URL.Path) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		aw := &accessWriter{ResponseWriter: w}
+		next.ServeHTTP(aw, r)
+
+		if aw.status == 0 {
+			aw.status = http.StatusOK
+		}
+
+		n := atomic.AddUint64(&cnt, 1)
+		if opt.Sample > 1 && aw.status < 400 && n%uint64(opt.Sample) != 0 {
+			return
+		}
+
+		p := opt.Log
+		if p == nil {
+			p = def()
+		}
+
+		s, ff := accessLine(opt.Format, r, aw, rid, start)
+		p.output(LevelNone, s, ff, false)
+	})
+}
+
+// RequestID #liefert die Request-ID aus dem Context
+func RequestID(r *http.Request) string {
+	s, _ := r.Context().Value(reqIDKey).(string)
+	return s
+}
+
+func (o *AccessOpt) excluded(path string) bool {
+	for _, e := range o.Exclude {
+		if strings.HasSuffix(e, "*") {
+			if strings.HasPrefix(path, e[:len(e)-1]) {
+				return true
+			}
+		} else if path == e {
+			return true
+		}
+	}
+
+	return false
+}
+
+func accessLine(format int, r *http.Request, aw *accessWriter, rid string, start time.Time) (string, []Field) {
+	dur := time.Since(start)
+	ip := RemoteIP(r)
+
+	switch format {
+	case AccessCommon, AccessCombined:
+		user := "-"
+		if u, _, ok := r.BasicAuth(); ok && u != "" {
+			user = u
+		}
+
+		s := fmt.Sprintf("%s - %s [%s] \"%s %s %s\" %d %d", ip, user,
+			start.Format("02/Jan/2006:15:04:05 -0700"),
+			r.Method, r.URL.RequestURI(), r.Proto, aw.status, aw.bytes)
+
+		if format == AccessCombined {
+			s += fmt.Sprintf(" %q %q", dash(r.Referer()), dash(r.UserAgent()))
+		}
+
+		return NoTime + s, nil
+	}
+
+	ff := []Field{
+		{"method", r.Method},
+		{"path", r.URL.Path},
+		{"status", aw.status},
+		{"bytes", aw.bytes},
+		{"dur", dur.Round(time.Microsecond).String()},
+		{"ip", ip},
+		{"rid", rid},
+	}
+
+	if format == AccessJSON {
+		m := make(map[string]interface{}, len(ff)+1)
+		m["time"] = start.Format(time.RFC3339)
+		for _, f := range ff {
+			m[f.Key] = f.Value
+		}
+
+		b, _ := json.Marshal(m)
+		return NoTime + string(b), nil
+	}
+
+	return "access", ff
+}
+
+// RemoteIP #Client-IP; kommt der Request von einem TrustedProxies, dann die
+// letzte nicht vertrauenswuerdige Adresse aus X-Forwarded-For bzw. X-Real-Ip
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	if !trustedProxy(host) {
+		return host
+	}
+
+	if s := r.Header.Get("X-Forwarded-For"); s != "" {
+		ss := strings.Split(s, ",")
+		for i := len(ss) - 1; i >= 0; i-- {
+			ip := strings.TrimSpace(ss[i])
+			if i == 0 || !trustedProxy(ip) {
+				return ip
+			}
+		}
+	}
+
+	if s := r.Header.Get("X-Real-Ip"); s != "" {
+		return strings.TrimSpace(s)
+	}
+
+	return host
+}
+
+func trustedProxy(s string) bool {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+
+	for _, p := range TrustedProxies {
+		if _, n, err := net.ParseCIDR(p); err == nil {
+			if n.Contains(ip) {
+				return true
+			}
+		} else if x := net.ParseIP(p); x != nil && x.Equal(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+
+	return s
+}
+
+// accessWriter #merkt sich Status und Anzahl Bytes
+type accessWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (w *accessWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *accessWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush #http.Flusher
+func (w *accessWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack #http.Hijacker (websocket)
+func (w *accessWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, errors.New("hijack not supported")
+}
@@ -0,0 +1,95 @@
+package lgx
+
+// ----------------------------------------------------------------------------------
+// caller.go (https://github.com/waldurbas/got): caller location and stack traces
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+
+import (
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// MaxStack #max. Anzahl Frames bei LgxStack
+var MaxStack = 16
+
+// lgxPkg #"github.com/waldurbas/got/lgx."
+var lgxPkg string
+
+func init() {
+	pc, _, _, _ := runtime.Caller(0)
+	fn := runtime.FuncForPC(pc).Name()
+	ix := strings.LastIndex(fn, "/") + 1
+	lgxPkg = fn[:ix+strings.Index(fn[ix:], ".")+1]
+}
+
+// withCaller #"file.go:12 pkg.Func: " vor s, bei stk den Stack hinter s
+func (p *Lgx) withCaller(s string, stk bool) string {
+	pcs := make([]uintptr, MaxStack+8)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var (
+		sb    strings.Builder
+		first = true
+		cnt   = 0
+	)
+
+	stk = stk && p.prop&LgxStack != 0
+
+	for {
+		fr, more := frames.Next()
+		if strings.HasPrefix(fr.Function, lgxPkg) {
+			if !more {
+				break
+			}
+			continue
+		}
+
+		if first {
+			first = false
+			if p.prop&LgxCaller != 0 {
+				s = path.Base(fr.File) + ":" + strconv.Itoa(fr.Line) + " " + shortFunc(fr.Function) + ": " + s
+			}
+
+			if !stk {
+				break
+			}
+
+			s = strings.TrimRight(s, "\r\n")
+		}
+
+		if fr.Function == "runtime.goexit" || fr.Function == "runtime.main" || cnt >= MaxStack {
+			break
+		}
+
+		sb.WriteString(NewLine + "\t" + fr.Function + NewLine + "\t\t" + fr.File + ":" + strconv.Itoa(fr.Line))
+		cnt++
+
+		if !more {
+			break
+		}
+	}
+
+	return s + sb.String()
+}
+
+// shortFunc #github.com/a/b/pkg.(*T).Func -> pkg.(*T).Func
+func shortFunc(fn string) string {
+	if ix := strings.LastIndex(fn, "/"); ix >= 0 {
+		return fn[ix+1:]
+	}
+
+	return fn
+}
@@ -0,0 +1,64 @@
+package lgx
+
+// ----------------------------------------------------------------------------------
+// field.go (https://github.com/waldurbas/got): structured fields
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Field #key=value fuer strukturierte Log-Zeilen
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// F #
+func F(key string, v interface{}) Field {
+	return Field{Key: key, Value: v}
+}
+
+// FormatFields #msg key=value key=value ...
+func FormatFields(msg string, ff ...Field) string {
+	var sb strings.Builder
+
+	sb.WriteString(msg)
+	for _, f := range ff {
+		if sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		sb.WriteString(f.Key)
+		sb.WriteByte('=')
+
+		s := fmt.Sprint(f.Value)
+		if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+			s = strconv.Quote(s)
+		}
+		sb.WriteString(s)
+	}
+
+	return sb.String()
+}
+
+// PrintFields #
+func (p *Lgx) PrintFields(msg string, ff ...Field) string {
+	return p.output(LevelNone, msg, ff, false)
+}
+
+// PrintFields #
+func PrintFields(msg string, ff ...Field) string {
+	return def().PrintFields(msg, ff...)
+}
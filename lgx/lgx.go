@@ -11,6 +11,16 @@ package lgx
 // ----------------------------------------------------------------------------------
 // HISTORY
 //-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) StartLog ohne LgxRedact (Opt-in ueber StartLogProp), Write an Sinks
+// 2026.10.19 (wu) NoTime vor Caller-Info
+// 2026.10.19 (wu) SetDefault unter stdMu
+// 2026.10.19 (wu) Fatal mit LevelFatal
+// 2026.10.19 (wu) Sink, SetDefault
+// 2026.10.19 (wu) LgxCaller, LgxStack
+// 2026.10.19 (wu) DayLogFile
+// 2026.10.19 (wu) LgxRedact
+// 2026.10.19 (wu) Level, SetSuppress
+// 2026.10.19 (wu) AccessLog, PrintFields
 // 2022.03.10 (wu) PathJoinSep
 // 2020.03.30 (wu) SetVersion
 //                 ab go 1.16 funktioniert -ldflags "-X lgx.xVersion=$Version" nicht mehr ??
@@ -56,6 +66,9 @@ var (
 	LFchar         string
 	NewLinePrinted bool
 	LinePfx        string
+
+	// gmu #NewLinePrinted und LinePfx werden von allen Loggern geschrieben
+	gmu sync.Mutex
 )
 
 // Lgx #
@@ -69,20 +82,71 @@ type Lgx struct {
 	excName     string // execname without Directory
 	LogDir      string
 	LogFileName string
+
+	sup map[Level]*Suppress // Unterdrueckung je Level
+	lim map[string]*limEntry
+	rep repEntry
+	swp time.Time
+	tmr *time.Timer
+	red *redactor
+
+	sinks []Sink
 }
 
 // LGX_STD #Standard mit Time
 // LGX_GCP #GoogleCloud ohne Time
+// LgxRedact #Geheimnisse (Token, Passwoerter) vor dem Schreiben entfernen
+// LgxCaller #file:line und Funktion des Aufrufers
+// LgxStack  #Error und Fatal mit Stack
 const (
-	LgxStd   = 0
-	LgxGcp   = 1
-	LgxDebug = 2
-	LgxFile  = 4
+	LgxStd    = 0
+	LgxGcp    = 1
+	LgxDebug  = 2
+	LgxFile   = 4
+	LgxRedact = 8
+	LgxCaller = 16
+	LgxStack  = 32
 
 	NoTime = "!~!"
 	NoNL   = '#'
 )
 
+// Level #
+type Level int
+
+// LevelNone #Print, Printf ohne Level
+const (
+	LevelNone Level = iota
+	LevelDebug
+	LevelInfo
+	LevelError
+	LevelFatal
+)
+
+// String #
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelError:
+		return "ERROR"
+	case LevelFatal:
+		return "FATAL"
+	}
+
+	return ""
+}
+
+func (l Level) pfx() string {
+	if l == LevelNone {
+		return ""
+	}
+
+	return "[" + l.String() + "] "
+}
+
 func init() {
 	CRchar = string([]byte{13})
 	LFchar = string([]byte{10})
@@ -114,21 +178,25 @@ func New(out io.Writer, prop int) *Lgx {
 func (p *Lgx) Write(b []byte) (n int, err error) {
 	le := len(b)
 	if le > 0 {
+		p.mu.Lock()
+		if p.prop&LgxRedact != 0 {
+			b = []byte(p.redact(string(b)))
+		}
+		if len(p.sinks) > 0 {
+			p.toSinks(LevelNone, string(b), nil)
+		}
+		p.mu.Unlock()
+
 		t := time.Now()
-		sti := fmt.Sprintf("%d-%02d-%02d %02d:%02d:%02d ",
-			t.Year(), t.Month(), t.Day(),
-			t.Hour(), t.Minute(), t.Second())
 
 		if p.out != nil {
 			p.out.Write(b)
 		}
 
 		if (p.prop & LgxFile) == LgxFile {
-			sti = strings.ReplaceAll(sti[0:10], "-", "")
-			logFileName := PathJoin(p.LogDir, sti[0:4], sti[4:6])
-			p.LogFileName = PathJoin(logFileName, p.logFilePfx+sti+".log")
+			p.LogFileName = DayLogFile(p.LogDir, p.logFilePfx, t)
 
-			if CreateDirIfNotExist(logFileName) != -1 {
+			if CreateDirIfNotExist(PathDir(p.LogFileName)) != -1 {
 				appendFile(p.LogFileName, string(b))
 			}
 		}
@@ -149,13 +217,53 @@ func (p *Lgx) SetOutput(w io.Writer) io.Writer {
 }
 
 func (p *Lgx) write(s string) string {
+	return p.lwrite(LevelNone, s)
+}
+
+func (p *Lgx) lwrite(lvl Level, s string) string {
+	return p.output(lvl, s, nil, lvl >= LevelError)
+}
+
+// output #ff: strukturierte Felder, stk: mit Stack bei LgxStack
+func (p *Lgx) output(lvl Level, s string, ff []Field, stk bool) string {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	return p._write(s)
+	if p.prop&LgxRedact != 0 {
+		s = p.redact(s)
+		ff = p.red.redactFields(ff)
+	}
+
+	msg := s
+	if len(ff) > 0 {
+		s = FormatFields(s, ff...)
+	}
+
+	if !p.allow(lvl, s) {
+		return ""
+	}
+
+	if len(p.sinks) > 0 {
+		p.toSinks(lvl, msg, ff)
+	}
+
+	// NoTime muss vorne bleiben (AccessLog)
+	mark := ""
+	if strings.HasPrefix(s, NoTime) {
+		mark, s = NoTime, s[len(NoTime):]
+	}
+
+	if p.prop&(LgxCaller|LgxStack) != 0 {
+		s = p.withCaller(s, stk)
+	}
+
+	return p._write(mark + lvl.pfx() + s)
 }
 
 func (p *Lgx) _write(s string) string {
+	gmu.Lock()
+	defer gmu.Unlock()
+
 	le := len(s)
 	addNL := le == 0
 	noNL := false
@@ -188,7 +296,9 @@ func (p *Lgx) _write(s string) string {
 				s = s[1:le]
 				le--
 			}
-			p.out.Write(p.buf)
+			if p.out != nil {
+				p.out.Write(p.buf)
+			}
 			p.buf = p.buf[:0]
 		}
 
@@ -234,15 +344,13 @@ func (p *Lgx) _write(s string) string {
 	}
 
 	if (p.prop & LgxFile) == LgxFile {
-		sti = strings.ReplaceAll(sti[0:10], "-", "")
-		logFileName := PathJoin(p.LogDir, sti[0:4], sti[4:6])
-		p.LogFileName = PathJoin(logFileName, p.logFilePfx+sti+".log")
+		p.LogFileName = DayLogFile(p.LogDir, p.logFilePfx, t)
 
 		if addNL && noNL {
 			p.buf = append(p.buf, NewLine...)
 		}
 
-		if CreateDirIfNotExist(logFileName) != -1 {
+		if CreateDirIfNotExist(PathDir(p.LogFileName)) != -1 {
 			appendFile(p.LogFileName, string(p.buf))
 		}
 	}
@@ -252,13 +360,15 @@ func (p *Lgx) _write(s string) string {
 
 // Fatal #
 func (p *Lgx) Fatal(v ...interface{}) {
-	p.Println(v...)
+	p.Flush()
+	p.output(LevelFatal, fmt.Sprintln(v...), nil, true)
 	os.Exit(-1)
 }
 
 // Fatalf #
 func (p *Lgx) Fatalf(frm string, v ...interface{}) {
-	p.Printf(frm, v...)
+	p.Flush()
+	p.output(LevelFatal, fmt.Sprintf(frm, v...), nil, true)
 	os.Exit(-1)
 }
 
@@ -278,94 +388,125 @@ func (p *Lgx) Print(v ...interface{}) string {
 }
 
 //------------- Standard ------------------------
-var std = New(os.Stderr, 0)
+var (
+	std   = New(os.Stderr, 0)
+	stdMu sync.RWMutex
+)
+
+// def #Standard-Logger, SetDefault kann parallel laufen
+func def() *Lgx {
+	stdMu.RLock()
+	defer stdMu.RUnlock()
+
+	return std
+}
 
 // IsDebug #
 var IsDebug = false
 
 // Default Logger
 func Default() *Lgx {
-	return std
+	return def()
+}
+
+// SetDefault #ersetzt den Standard-Logger, liefert den alten
+func SetDefault(l *Lgx) *Lgx {
+	stdMu.Lock()
+	defer stdMu.Unlock()
+
+	o := std
+	std = l
+	return o
 }
 
 // CurDir #
 func CurDir() string {
-	return std.curDir
+	return def().curDir
 }
 
 // LogDir #
 func LogDir() string {
 	t := time.Now()
 	sti := fmt.Sprintf("%d%02d", t.Year(), t.Month())
-	sLog := PathJoin(std.LogDir, sti[:4], sti[4:])
+	sLog := PathJoin(def().LogDir, sti[:4], sti[4:])
 	CreateDirIfNotExist(sLog)
 
 	return sLog
 }
 
+// DayLogFile #{dir}/{YYYY}/{MM}/{pfx}{YYYYMMDD}.log
+func DayLogFile(dir string, pfx string, t time.Time) string {
+	sti := fmt.Sprintf("%d%02d%02d", t.Year(), t.Month(), t.Day())
+	return PathJoin(dir, sti[:4], sti[4:6], pfx+sti+".log")
+}
+
 // ExecName #
 func ExecName() string {
-	return std.excName
+	return def().excName
 }
 
 // Println #
 func Println(v ...interface{}) {
-	std.write(fmt.Sprintln(v...))
+	def().write(fmt.Sprintln(v...))
 }
 
 // Print #
 func Print(v ...interface{}) string {
-	return std.write(fmt.Sprint(v...))
+	return def().write(fmt.Sprint(v...))
 }
 
 // PrintDebug #
 func PrintDebug(v ...interface{}) {
-	if IsDebug || (std.prop&LgxDebug) == LgxDebug {
-		std.write("[DEBUG] " + fmt.Sprintln(v...))
+	if IsDebug || (def().prop&LgxDebug) == LgxDebug {
+		def().lwrite(LevelDebug, fmt.Sprintln(v...))
 	}
 }
 
 // PrintInfo #
 func PrintInfo(v ...interface{}) {
-	std.write("[INFO] " + fmt.Sprintln(v...))
+	def().lwrite(LevelInfo, fmt.Sprintln(v...))
 }
 
 // PrintError #
 func PrintError(v ...interface{}) {
-	std.write("[ERROR] " + fmt.Sprintln(v...))
+	def().lwrite(LevelError, fmt.Sprintln(v...))
 }
 
 // Printf #
 func Printf(format string, v ...interface{}) string {
-	return std.write(fmt.Sprintf(format, v...))
+	return def().write(fmt.Sprintf(format, v...))
 }
 
 // PrintfDebug #
 func PrintfDebug(format string, v ...interface{}) {
-	if IsDebug || (std.prop&LgxDebug) == LgxDebug {
-		std.write("[DEBUG] " + fmt.Sprintf(format, v...))
+	if IsDebug || (def().prop&LgxDebug) == LgxDebug {
+		def().lwrite(LevelDebug, fmt.Sprintf(format, v...))
 	}
 }
 
 // PrintfInfo #
 func PrintfInfo(format string, v ...interface{}) {
-	std.write("[INFO] " + fmt.Sprintf(format, v...))
+	def().lwrite(LevelInfo, fmt.Sprintf(format, v...))
 }
 
 // PrintfError #
 func PrintfError(format string, v ...interface{}) {
-	std.write("[ERROR] " + fmt.Sprintf(format, v...))
+	def().lwrite(LevelError, fmt.Sprintf(format, v...))
 }
 
 // Fatal #
 func Fatal(v ...interface{}) {
-	std.write("[FATAL] " + fmt.Sprintln(v...))
+	l := def()
+	l.Flush()
+	l.lwrite(LevelFatal, fmt.Sprintln(v...))
 	os.Exit(1)
 }
 
 // Fatalf #
 func Fatalf(format string, v ...interface{}) {
-	std.write("[FATAL] " + fmt.Sprintf(format, v...))
+	l := def()
+	l.Flush()
+	l.lwrite(LevelFatal, fmt.Sprintf(format, v...))
 	os.Exit(1)
 }
 
@@ -440,21 +581,22 @@ func PathJoinSep(ps string, elem ...string) string {
 
 // Start #
 func Start(w io.Writer, info string, prop int, dir string, pfx string) {
-	std.mu.Lock()
-	defer std.mu.Unlock()
+	l := def()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
 	IsDebug = atob(os.Getenv("DEBUG"))
-	std.prop = prop
-	std.out = w
-	std.LogDir = dir
-	std.logFilePfx = pfx
+	l.prop = prop
+	l.out = w
+	l.LogDir = dir
+	l.logFilePfx = pfx
 	if dir != "" {
-		std.prop |= LgxFile
+		l.prop |= LgxFile
 	}
 
-	std._write("")
+	l._write("")
 	if len(info) > 0 {
-		std._write(NoTime + info)
+		l._write(NoTime + info)
 	}
 }
 
@@ -470,7 +612,7 @@ func printOut(w io.Writer, format string, v ...interface{}) {
 
 // PrintNL #
 func PrintNL() {
-	Fprintf(std.out, NewLine)
+	Fprintf(def().out, NewLine)
 	NewLinePrinted = true
 }
 
@@ -486,15 +628,16 @@ func PrintStdout(format string, v ...interface{}) {
 
 // SetProp #
 func SetProp(prop int) {
-	std.mu.Lock()
-	defer std.mu.Unlock()
+	l := def()
+	l.mu.Lock()
+	defer l.mu.Unlock()
 
-	std.prop = prop
+	l.prop = prop
 }
 
 // SetOutput #liefert alten Writer
 func SetOutput(w io.Writer) io.Writer {
-	return std.SetOutput(w)
+	return def().SetOutput(w)
 }
 
 func atob(s string) bool {
@@ -614,6 +757,9 @@ func SearchFilesOlderAs(dir string, days int) *[]string {
 	return &files
 }
 
+// StartLogProp #zusaetzliche Flags fuer StartLog, z.B. LgxRedact (Opt-in)
+var StartLogProp = 0
+
 // StartLog #Parameter
 // out: os.stderr || os.stdout
 // ldir: z.B.: /usr/firma/log
@@ -621,7 +767,7 @@ func SearchFilesOlderAs(dir string, days int) *[]string {
 //----------------------------------------------------------
 // logfile unter {ldir}/{JAMO}/{prgname}{YYMMDD}.log
 func StartLog(out *os.File, ldir string, cpyRight string) {
-	prop := 0
+	prop := StartLogProp
 
 	s := strings.Split(xVersion, ".")
 
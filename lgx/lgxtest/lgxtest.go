@@ -0,0 +1,201 @@
+package lgxtest
+
+// ----------------------------------------------------------------------------------
+// lgxtest.go (https://github.com/waldurbas/got): capture and assert lgx output in tests
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+//
+//	func TestX(t *testing.T) {
+//		lgxtest.New(t)
+//		doSomething()
+//		lgxtest.AssertLogged(t, lgx.LevelError, "backend down")
+//	}
+//
+// New ersetzt den globalen Standard-Logger, Tests mit t.Parallel() sind nicht moeglich.
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/waldurbas/got/lgx"
+)
+
+// Recorder #lgx.Sink, merkt sich alle Meldungen
+type Recorder struct {
+	mu      sync.Mutex
+	entries []lgx.Entry
+}
+
+var (
+	mu   sync.Mutex
+	recs = make(map[testing.TB]*Recorder)
+)
+
+// New #Standard-Logger mit Recorder, Ausgabe ueber t.Log
+// beim t.Cleanup wird der vorherige Logger wiederhergestellt
+func New(t testing.TB) *Recorder {
+	t.Helper()
+
+	prgName, isDebug, linePfx := lgx.PrgName, lgx.IsDebug, lgx.LinePfx
+
+	r := &Recorder{}
+	l := lgx.New(&tWriter{t}, lgx.LgxDebug)
+	l.AddSink(r)
+
+	old := lgx.SetDefault(l)
+	lgx.PrgName = prgName
+
+	mu.Lock()
+	recs[t] = r
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		lgx.SetDefault(old)
+		lgx.PrgName, lgx.IsDebug, lgx.LinePfx = prgName, isDebug, linePfx
+
+		mu.Lock()
+		delete(recs, t)
+		mu.Unlock()
+	})
+
+	return r
+}
+
+// WriteEntry #lgx.Sink
+func (r *Recorder) WriteEntry(e *lgx.Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	c := *e
+	c.Fields = append([]lgx.Field(nil), e.Fields...)
+	r.entries = append(r.entries, c)
+}
+
+// Entries #Kopie aller Meldungen
+func (r *Recorder) Entries() []lgx.Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]lgx.Entry(nil), r.entries...)
+}
+
+// Reset #
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries = nil
+}
+
+// Find #Meldungen mit lvl (LevelNone: alle Level), deren Text sub enthaelt
+func (r *Recorder) Find(lvl lgx.Level, sub string) []lgx.Entry {
+	var ee []lgx.Entry
+	for _, e := range r.Entries() {
+		if lvl != lgx.LevelNone && e.Level != lvl {
+			continue
+		}
+
+		if strings.Contains(lgx.FormatFields(e.Msg, e.Fields...), sub) {
+			ee = append(ee, e)
+		}
+	}
+
+	return ee
+}
+
+// Field #Wert des Feldes key der ersten passenden Meldung
+func (r *Recorder) Field(lvl lgx.Level, sub string, key string) (interface{}, bool) {
+	for _, e := range r.Find(lvl, sub) {
+		for _, f := range e.Fields {
+			if f.Key == key {
+				return f.Value, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// AssertLogged #
+func (r *Recorder) AssertLogged(t testing.TB, lvl lgx.Level, sub string) {
+	t.Helper()
+
+	if len(r.Find(lvl, sub)) == 0 {
+		t.Errorf("lgxtest: no %s entry containing %q\n%s", lvlName(lvl), sub, r.dump())
+	}
+}
+
+// AssertNotLogged #
+func (r *Recorder) AssertNotLogged(t testing.TB, lvl lgx.Level, sub string) {
+	t.Helper()
+
+	if ee := r.Find(lvl, sub); len(ee) > 0 {
+		t.Errorf("lgxtest: unexpected %s entry containing %q: %s", lvlName(lvl), sub, ee[0].Msg)
+	}
+}
+
+// AssertLogged #mit dem Recorder von New(t)
+func AssertLogged(t testing.TB, lvl lgx.Level, sub string) {
+	t.Helper()
+	recorder(t).AssertLogged(t, lvl, sub)
+}
+
+// AssertNotLogged #mit dem Recorder von New(t)
+func AssertNotLogged(t testing.TB, lvl lgx.Level, sub string) {
+	t.Helper()
+	recorder(t).AssertNotLogged(t, lvl, sub)
+}
+
+func recorder(t testing.TB) *Recorder {
+	t.Helper()
+
+	mu.Lock()
+	r := recs[t]
+	mu.Unlock()
+
+	if r == nil {
+		t.Fatalf("lgxtest: New(t) not called")
+	}
+
+	return r
+}
+
+func (r *Recorder) dump() string {
+	var sb strings.Builder
+	for _, e := range r.Entries() {
+		sb.WriteString("\t[" + lvlName(e.Level) + "] " + lgx.FormatFields(e.Msg, e.Fields...) + "\n")
+	}
+
+	return sb.String()
+}
+
+func lvlName(lvl lgx.Level) string {
+	if lvl == lgx.LevelNone {
+		return "any"
+	}
+
+	return lvl.String()
+}
+
+// tWriter #Log-Ausgabe ueber t.Log
+type tWriter struct {
+	t testing.TB
+}
+
+func (w *tWriter) Write(b []byte) (int, error) {
+	if s := strings.TrimRight(string(b), "\r\n"); s != "" {
+		w.t.Log(s)
+	}
+
+	return len(b), nil
+}
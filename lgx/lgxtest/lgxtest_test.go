@@ -0,0 +1,38 @@
+package lgxtest_test
+
+import (
+	"testing"
+
+	"github.com/waldurbas/got/lgx"
+	"github.com/waldurbas/got/lgx/lgxtest"
+)
+
+func Test_Recorder(t *testing.T) {
+	old := lgx.Default()
+
+	t.Run("capture", func(t *testing.T) {
+		r := lgxtest.New(t)
+
+		lgx.PrintError("backend down:", "db1")
+		lgx.PrintfInfo("started %d workers", 4)
+		lgx.PrintDebug("debug line")
+		lgx.PrintFields("login", lgx.F("user", "wu"), lgx.F("ok", true))
+
+		lgxtest.AssertLogged(t, lgx.LevelError, "backend down: db1")
+		lgxtest.AssertLogged(t, lgx.LevelInfo, "4 workers")
+		lgxtest.AssertLogged(t, lgx.LevelDebug, "debug line")
+		lgxtest.AssertNotLogged(t, lgx.LevelError, "workers")
+
+		if v, ok := r.Field(lgx.LevelNone, "login", "user"); !ok || v != "wu" {
+			t.Errorf("Field user: %v %v", v, ok)
+		}
+
+		if n := len(r.Entries()); n != 4 {
+			t.Errorf("Entries: %d", n)
+		}
+	})
+
+	if lgx.Default() != old {
+		t.Errorf("Default logger not restored")
+	}
+}
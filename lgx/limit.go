@@ -0,0 +1,219 @@
+package lgx
+
+// ----------------------------------------------------------------------------------
+// limit.go (https://github.com/waldurbas/got): rate limit and dedup of log lines
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Summenzeilen auch an Sinks
+// 2026.10.19 (wu) Summenzeilen auch ohne weitere Meldungen nach Per (Timer), Fatal nie unterdrueckt
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Suppress #Unterdrueckung wiederholter Meldungen eines Levels
+// unterdrueckte Meldungen werden als Summenzeile nachgeliefert
+type Suppress struct {
+	Max   int           // max. gleiche Meldungen je Per, 0: ohne Limit
+	Per   time.Duration // Zeitraum fuer Max, Default 1 Minute
+	Dedup bool          // direkt wiederholte Zeilen: "last message repeated N times"
+}
+
+type limEntry struct {
+	lvl     Level
+	msg     string
+	per     time.Duration
+	start   time.Time
+	n       int
+	dropped int
+}
+
+type repEntry struct {
+	lvl Level
+	key string
+	n   int
+	per time.Duration
+}
+
+// SetSuppress #nil schaltet die Unterdrueckung fuer lvl ab
+func (p *Lgx) SetSuppress(lvl Level, s *Suppress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if s == nil {
+		delete(p.sup, lvl)
+		return
+	}
+
+	if p.sup == nil {
+		p.sup = make(map[Level]*Suppress)
+		p.lim = make(map[string]*limEntry)
+	}
+
+	c := *s
+	if c.Per <= 0 {
+		c.Per = time.Minute
+	}
+	p.sup[lvl] = &c
+}
+
+// SetSuppress #
+func SetSuppress(lvl Level, s *Suppress) {
+	def().SetSuppress(lvl, s)
+}
+
+// Flush #schreibt ausstehende Summenzeilen
+func (p *Lgx) Flush() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.flushRep()
+	for k, e := range p.lim {
+		p.flushLim(e)
+		delete(p.lim, k)
+	}
+}
+
+// Flush #
+func Flush() {
+	def().Flush()
+}
+
+// allow #false: Meldung wird unterdrueckt, p.mu ist gesperrt
+func (p *Lgx) allow(lvl Level, s string) bool {
+	if len(p.sup) == 0 && p.rep.n == 0 && len(p.lim) == 0 {
+		return true
+	}
+
+	// Fatal nie unterdruecken
+	if lvl == LevelFatal {
+		p.flushRep()
+		p.rep.key = ""
+		return true
+	}
+
+	now := time.Now()
+	key := lvl.pfx() + strings.TrimSpace(s)
+	sp := p.sup[lvl]
+
+	if sp != nil && sp.Dedup && key == p.rep.key {
+		p.rep.n++
+		if p.rep.n == 1 {
+			p.arm(p.rep.per)
+		}
+		return false
+	}
+
+	p.flushRep()
+	p.rep.key = ""
+
+	if sp != nil && sp.Max > 0 {
+		e := p.lim[key]
+		if e == nil {
+			e = &limEntry{lvl: lvl, msg: strings.TrimSpace(s), per: sp.Per, start: now}
+			p.lim[key] = e
+		} else if now.Sub(e.start) >= e.per {
+			p.flushLim(e)
+			e.start = now
+			e.n = 0
+		}
+
+		e.n++
+		if e.n > sp.Max {
+			e.dropped++
+			if e.dropped == 1 {
+				p.arm(e.start.Add(e.per).Sub(now))
+			}
+			return false
+		}
+	}
+
+	if sp != nil && sp.Dedup {
+		p.rep.key = key
+		p.rep.lvl = lvl
+		p.rep.per = sp.Per
+	}
+
+	p.sweep(now)
+	return true
+}
+
+// sweep #abgelaufene Eintraege melden und entfernen, max. 1x je Sekunde
+func (p *Lgx) sweep(now time.Time) {
+	if now.Sub(p.swp) < time.Second {
+		return
+	}
+	p.swp = now
+
+	for k, e := range p.lim {
+		if now.Sub(e.start) >= e.per {
+			p.flushLim(e)
+			delete(p.lim, k)
+		}
+	}
+}
+
+// arm #Timer fuer ausstehende Summenzeilen, p.mu ist gesperrt
+func (p *Lgx) arm(d time.Duration) {
+	if p.tmr == nil {
+		p.tmr = time.AfterFunc(d, p.tick)
+	}
+}
+
+// tick #Summenzeilen ausgeben, auch wenn keine weitere Meldung kommt
+func (p *Lgx) tick() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.tmr = nil
+	now := time.Now()
+
+	p.flushRep()
+	p.swp = time.Time{}
+	p.sweep(now)
+
+	// Eintraege, deren Zeitraum noch laeuft
+	var next time.Duration
+	for _, e := range p.lim {
+		if d := e.start.Add(e.per).Sub(now); e.dropped > 0 && (next == 0 || d < next) {
+			next = d
+		}
+	}
+	if next > 0 {
+		p.arm(next)
+	}
+}
+
+func (p *Lgx) flushRep() {
+	if p.rep.n > 0 {
+		p.summary(p.rep.lvl, fmt.Sprintf("last message repeated %d times", p.rep.n))
+		p.rep.n = 0
+	}
+}
+
+func (p *Lgx) flushLim(e *limEntry) {
+	if e.dropped > 0 {
+		p.summary(e.lvl, fmt.Sprintf("%d messages suppressed: %s", e.dropped, e.msg))
+		e.dropped = 0
+	}
+}
+
+// summary #wie output, ohne erneute Pruefung durch allow; unter p.mu
+func (p *Lgx) summary(lvl Level, s string) {
+	if len(p.sinks) > 0 {
+		p.toSinks(lvl, s, nil)
+	}
+
+	p._write(lvl.pfx() + s)
+}
@@ -0,0 +1,74 @@
+package lgx
+
+// ----------------------------------------------------------------------------------
+// sink.go (https://github.com/waldurbas/got): additional log destinations
+// Copyright 2026 by Waldemar Urbas
+//-----------------------------------------------------------------------------------
+// This Source Code Form is subject to the terms of the 'MIT License'
+// A short and simple permissive license with conditions only requiring
+// preservation of copyright and license notices.  Licensed works, modifications,
+// and larger works may be distributed under different terms and without source code.
+// ----------------------------------------------------------------------------------
+// HISTORY
+//-----------------------------------------------------------------------------------
+// 2026.10.19 (wu) Init
+//-----------------------------------------------------------------------------------
+
+import (
+	"strings"
+	"time"
+)
+
+// Entry #eine Log-Meldung, Msg ohne Zeit und Level
+type Entry struct {
+	Time   time.Time
+	Level  Level
+	Msg    string
+	Fields []Field
+}
+
+// Sink #erhaelt jede Meldung nach Redaction und Unterdrueckung
+type Sink interface {
+	WriteEntry(e *Entry)
+}
+
+// AddSink #
+func (p *Lgx) AddSink(s Sink) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.sinks = append(p.sinks, s)
+}
+
+// RemoveSink #
+func (p *Lgx) RemoveSink(s Sink) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, x := range p.sinks {
+		if x == s {
+			p.sinks = append(p.sinks[:i], p.sinks[i+1:]...)
+			return
+		}
+	}
+}
+
+// AddSink #
+func AddSink(s Sink) {
+	def().AddSink(s)
+}
+
+// RemoveSink #
+func RemoveSink(s Sink) {
+	def().RemoveSink(s)
+}
+
+// toSinks #p.mu ist gesperrt
+func (p *Lgx) toSinks(lvl Level, s string, ff []Field) {
+	s = strings.TrimRight(strings.TrimPrefix(s, NoTime), "\r\n")
+
+	e := &Entry{Time: time.Now(), Level: lvl, Msg: s, Fields: ff}
+	for _, sk := range p.sinks {
+		sk.WriteEntry(e)
+	}
+}